@@ -0,0 +1,164 @@
+package imgcombine
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/nfnt/resize"
+)
+
+// Accelerator 图像处理加速后端，负责缩放、模糊和透明度合成等耗时的像素循环
+// 默认使用纯Go实现；amd64上CPU支持时内置AVX2实现("avx2")，以purego构建标签排除；也可注册GPU等实现并在运行时切换
+type Accelerator interface {
+	Name() string
+	Resize(img image.Image, width, height int) image.Image // 高质量缩放(Lanczos3)
+	Blur(img image.Image, radius float64) image.Image      // 高斯模糊，radius为标准差(像素)
+	ApplyAlpha(img image.Image, alpha int) image.Image     // 整体透明度(0-255)
+}
+
+// AccelEnv 指定加速后端名称的环境变量，名称匹配的后端注册时即被启用，
+// 因此带构建标签或由其他包注册的后端无论初始化顺序如何都能被选中
+const AccelEnv = "IMGCOMBINE_ACCEL"
+
+var (
+	accelMu      sync.RWMutex
+	accelerators = map[string]Accelerator{"go": pureGoAccelerator{}}
+	accelerator  = Accelerator(pureGoAccelerator{})
+)
+
+// RegisterAccelerator 注册加速后端，可在init中由带构建标签的实现注册
+// 名称与环境变量AccelEnv相同时立即切换到该后端
+func RegisterAccelerator(a Accelerator) {
+	accelMu.Lock()
+	defer accelMu.Unlock()
+	accelerators[a.Name()] = a
+	if a.Name() == os.Getenv(AccelEnv) {
+		accelerator = a
+	}
+}
+
+// UseAccelerator 按名称切换加速后端，"go"为纯Go实现
+func UseAccelerator(name string) error {
+	accelMu.Lock()
+	defer accelMu.Unlock()
+	a, ok := accelerators[name]
+	if !ok {
+		return fmt.Errorf("accelerator %q is not registered", name)
+	}
+	accelerator = a
+	return nil
+}
+
+// CurrentAccelerator 返回当前使用的加速后端
+func CurrentAccelerator() Accelerator {
+	accelMu.RLock()
+	defer accelMu.RUnlock()
+	return accelerator
+}
+
+// pureGoAccelerator 纯Go实现，所有平台可用
+type pureGoAccelerator struct{}
+
+func (pureGoAccelerator) Name() string { return "go" }
+
+func (pureGoAccelerator) Resize(img image.Image, width, height int) image.Image {
+	return resize.Resize(uint(width), uint(height), img, resize.Lanczos3)
+}
+
+func (pureGoAccelerator) ApplyAlpha(img image.Image, alpha int) image.Image {
+	return applyAlpha(img, alpha)
+}
+
+func (pureGoAccelerator) Blur(img image.Image, radius float64) image.Image {
+	return boxBlur(img, radius, boxBlurV)
+}
+
+// boxBlur 以三次盒式模糊近似高斯模糊，在预乘透明度的RGBA上计算，避免透明边缘发黑
+// blurV为垂直方向的盒式模糊实现，供不同后端替换
+func boxBlur(img image.Image, radius float64, blurV func(src, dst []uint8, w, h, r int)) image.Image {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	if radius <= 0 {
+		return rgba
+	}
+
+	tmp := make([]uint8, len(rgba.Pix))
+	for _, size := range gaussBoxSizes(radius, 3) {
+		r := (size - 1) / 2
+		boxBlurH(rgba.Pix, tmp, rgba.Rect.Dx(), rgba.Rect.Dy(), r)
+		blurV(tmp, rgba.Pix, rgba.Rect.Dx(), rgba.Rect.Dy(), r)
+	}
+	return rgba
+}
+
+// gaussBoxSizes 计算n次盒式模糊近似标准差为sigma的高斯模糊时各次的盒宽
+func gaussBoxSizes(sigma float64, n int) []int {
+	wIdeal := math.Sqrt(12*sigma*sigma/float64(n) + 1)
+	wl := int(wIdeal)
+	if wl%2 == 0 {
+		wl--
+	}
+	wu := wl + 2
+	mIdeal := (12*sigma*sigma - float64(n*wl*wl) - float64(4*n*wl) - float64(3*n)) / float64(-4*wl-4)
+	m := int(math.Round(mIdeal))
+
+	sizes := make([]int, n)
+	for i := range sizes {
+		if i < m {
+			sizes[i] = wl
+		} else {
+			sizes[i] = wu
+		}
+	}
+	return sizes
+}
+
+// boxBlurH 水平方向盒式模糊，边缘像素向外延伸
+func boxBlurH(src, dst []uint8, w, h, r int) {
+	if r <= 0 {
+		copy(dst, src)
+		return
+	}
+	div := 2*r + 1
+	for y := 0; y < h; y++ {
+		row := y * w * 4
+		for c := 0; c < 4; c++ {
+			at := func(x int) int { return int(src[row+clampInt(x, 0, w-1)*4+c]) }
+			sum := 0
+			for x := -r; x <= r; x++ {
+				sum += at(x)
+			}
+			for x := 0; x < w; x++ {
+				dst[row+x*4+c] = uint8((sum + div/2) / div)
+				sum += at(x+r+1) - at(x-r)
+			}
+		}
+	}
+}
+
+// boxBlurV 垂直方向盒式模糊，边缘像素向外延伸
+func boxBlurV(src, dst []uint8, w, h, r int) {
+	if r <= 0 {
+		copy(dst, src)
+		return
+	}
+	div := 2*r + 1
+	for x := 0; x < w; x++ {
+		for c := 0; c < 4; c++ {
+			at := func(y int) int { return int(src[clampInt(y, 0, h-1)*w*4+x*4+c]) }
+			sum := 0
+			for y := -r; y <= r; y++ {
+				sum += at(y)
+			}
+			for y := 0; y < h; y++ {
+				dst[y*w*4+x*4+c] = uint8((sum + div/2) / div)
+				sum += at(y+r+1) - at(y-r)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func cpuHasAVX2() bool
+TEXT ·cpuHasAVX2(SB), NOSPLIT, $0-1
+	// CPUID.1:ECX 需同时支持OSXSAVE(位27)和AVX(位28)
+	MOVL $1, AX
+	XORL CX, CX
+	CPUID
+	ANDL $0x18000000, CX
+	CMPL CX, $0x18000000
+	JNE  no
+
+	// XGETBV：操作系统需保存XMM(位1)和YMM(位2)寄存器状态
+	XORL CX, CX
+	BYTE $0x0f; BYTE $0x01; BYTE $0xd0
+	ANDL $6, AX
+	CMPL AX, $6
+	JNE  no
+
+	// CPUID.7.0:EBX 位5为AVX2
+	MOVL $7, AX
+	XORL CX, CX
+	CPUID
+	BTL  $5, BX
+	JCC  no
+	MOVB $1, ret+0(FP)
+	RET
+
+no:
+	MOVB $0, ret+0(FP)
+	RET
+
+// func scaleAlphaAVX2(pix []uint8, ratio float64)
+// 每次4个像素：透明度转为float64乘以ratio后截断，与纯Go实现的浮点运算一致
+TEXT ·scaleAlphaAVX2(SB), NOSPLIT, $0-32
+	MOVQ         pix_base+0(FP), SI
+	MOVQ         pix_len+8(FP), CX
+	VBROADCASTSD ratio+24(FP), Y2
+	MOVL         $0x00ffffff, AX
+	MOVQ         AX, X3
+	VPBROADCASTD X3, X3
+	SHRQ         $4, CX
+	JZ           alphadone
+
+alphaloop:
+	VMOVDQU     (SI), X0
+	VPSRLD      $24, X0, X1
+	VCVTDQ2PD   X1, Y1
+	VMULPD      Y2, Y1, Y1
+	VCVTTPD2DQY Y1, X1
+	VPSLLD      $24, X1, X1
+	VPAND       X3, X0, X0
+	VPOR        X1, X0, X0
+	VMOVDQU     X0, (SI)
+	ADDQ        $16, SI
+	DECQ        CX
+	JNZ         alphaloop
+
+alphadone:
+	VZEROUPPER
+	RET
+
+// func addRowAVX2(sums []int32, row []uint8)
+TEXT ·addRowAVX2(SB), NOSPLIT, $0-48
+	MOVQ sums_base+0(FP), DI
+	MOVQ row_base+24(FP), SI
+	MOVQ row_len+32(FP), CX
+	SHRQ $3, CX
+	JZ   adddone
+
+addloop:
+	VPMOVZXBD (SI), Y0
+	VPADDD    (DI), Y0, Y0
+	VMOVDQU   Y0, (DI)
+	ADDQ      $8, SI
+	ADDQ      $32, DI
+	DECQ      CX
+	JNZ       addloop
+
+adddone:
+	VZEROUPPER
+	RET
+
+// func slideRowAVX2(sums []int32, add, sub []uint8)
+TEXT ·slideRowAVX2(SB), NOSPLIT, $0-72
+	MOVQ sums_base+0(FP), DI
+	MOVQ add_base+24(FP), SI
+	MOVQ add_len+32(FP), CX
+	MOVQ sub_base+48(FP), DX
+	SHRQ $3, CX
+	JZ   slidedone
+
+slideloop:
+	VPMOVZXBD (SI), Y0
+	VPMOVZXBD (DX), Y1
+	VPSUBD    Y1, Y0, Y0
+	VPADDD    (DI), Y0, Y0
+	VMOVDQU   Y0, (DI)
+	ADDQ      $8, SI
+	ADDQ      $8, DX
+	ADDQ      $32, DI
+	DECQ      CX
+	JNZ       slideloop
+
+slidedone:
+	VZEROUPPER
+	RET
+
+// func divRowAVX2(dst []uint8, sums []int32, off, inv float32)
+// 每次8个通道：(sum + off) * inv截断为整数后饱和压缩为字节
+TEXT ·divRowAVX2(SB), NOSPLIT, $0-56
+	MOVQ         dst_base+0(FP), DI
+	MOVQ         dst_len+8(FP), CX
+	MOVQ         sums_base+24(FP), SI
+	VBROADCASTSS off+48(FP), Y2
+	VBROADCASTSS inv+52(FP), Y3
+	SHRQ         $3, CX
+	JZ           divdone
+
+divloop:
+	VCVTDQ2PS    (SI), Y0
+	VADDPS       Y2, Y0, Y0
+	VMULPS       Y3, Y0, Y0
+	VCVTTPS2DQ   Y0, Y0
+	VEXTRACTI128 $1, Y0, X1
+	VPACKUSDW    X1, X0, X0
+	VPACKUSWB    X0, X0, X0
+	MOVQ         X0, (DI)
+	ADDQ         $32, SI
+	ADDQ         $8, DI
+	DECQ         CX
+	JNZ          divloop
+
+divdone:
+	VZEROUPPER
+	RET
@@ -0,0 +1,100 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// countingAccelerator 记录调用次数的测试后端
+type countingAccelerator struct {
+	pureGoAccelerator
+	resizes int
+}
+
+func (c *countingAccelerator) Name() string { return "counting" }
+
+func (c *countingAccelerator) Resize(img image.Image, width, height int) image.Image {
+	c.resizes++
+	return c.pureGoAccelerator.Resize(img, width, height)
+}
+
+// TestAccelerator 测试加速后端的注册与切换，以及纯Go模糊实现
+func TestAccelerator(t *testing.T) {
+	if CurrentAccelerator().Name() != "go" {
+		t.Fatalf("默认应使用纯Go实现，实际 %s", CurrentAccelerator().Name())
+	}
+	if err := UseAccelerator("missing"); err == nil {
+		t.Error("未注册的后端应返回错误")
+	}
+
+	// 注册时按环境变量启用，与注册发生在包初始化之前还是之后无关
+	t.Setenv(AccelEnv, "counting")
+	counting := &countingAccelerator{}
+	RegisterAccelerator(counting)
+	defer UseAccelerator("go")
+	if CurrentAccelerator() != Accelerator(counting) {
+		t.Fatalf("注册与环境变量同名的后端应立即启用，实际 %s", CurrentAccelerator().Name())
+	}
+	if err := UseAccelerator("go"); err != nil {
+		t.Fatalf("切换后端失败: %v", err)
+	}
+	if err := UseAccelerator("counting"); err != nil {
+		t.Fatalf("切换后端失败: %v", err)
+	}
+
+	combiner := NewImageCombiner(100, 100)
+	combiner.AddElement(&ImageElement{image: newImage(color.White), Width: 20, Height: 20, ZoomMode: WidthHeight, Alpha: 255})
+	combiner.Combine()
+	if counting.resizes != 1 {
+		t.Errorf("图片缩放应由当前后端执行，调用次数 %d", counting.resizes)
+	}
+
+	// 单个白点模糊后向四周扩散，总亮度基本守恒
+	dot := image.NewRGBA(image.Rect(0, 0, 21, 21))
+	dot.Set(10, 10, color.White)
+	blurred := pureGoAccelerator{}.Blur(dot, 2).(*image.RGBA)
+	if blurred.RGBAAt(10, 10).A >= 255 || blurred.RGBAAt(12, 10).A == 0 || blurred.RGBAAt(10, 12).A == 0 {
+		t.Error("模糊后像素应向四周扩散")
+	}
+	sum := 0
+	for i := 3; i < len(blurred.Pix); i += 4 {
+		sum += int(blurred.Pix[i])
+	}
+	if sum < 200 || sum > 310 {
+		t.Errorf("模糊后透明度总和 %d 偏离原值255", sum)
+	}
+}
+
+// TestAVX2Accelerator 测试AVX2实现与纯Go实现的结果逐字节一致，CPU不支持时跳过
+func TestAVX2Accelerator(t *testing.T) {
+	accelMu.RLock()
+	avx2, ok := accelerators["avx2"]
+	accelMu.RUnlock()
+	if !ok {
+		t.Skip("AVX2不可用")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []image.Point{{1, 1}, {3, 5}, {37, 23}, {64, 64}} {
+		img := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		rng.Read(img.Pix)
+		for _, radius := range []float64{0.5, 2, 7, 120, 300} {
+			want := pureGoAccelerator{}.Blur(img, radius).(*image.RGBA)
+			got := avx2.Blur(img, radius).(*image.RGBA)
+			if !bytes.Equal(want.Pix, got.Pix) {
+				t.Errorf("%v 半径%.1f: 模糊结果与纯Go实现不一致", size, radius)
+			}
+		}
+		for alpha := 0; alpha < 256; alpha++ {
+			want := pureGoAccelerator{}.ApplyAlpha(img, alpha).(*image.RGBA)
+			got := avx2.ApplyAlpha(img, alpha).(*image.RGBA)
+			if !bytes.Equal(want.Pix, got.Pix) {
+				t.Errorf("%v 透明度%d: 结果与纯Go实现不一致", size, alpha)
+				break
+			}
+		}
+	}
+}
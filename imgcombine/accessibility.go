@@ -0,0 +1,76 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+// AccessibilityText 无障碍描述中的单段文本
+type AccessibilityText struct {
+	Order    int     `json:"order"`     // 阅读顺序，从1开始
+	Text     string  `json:"text"`      // 文本内容
+	X        int     `json:"x"`         // 文本位置
+	Y        int     `json:"y"`         // 文本位置
+	FontSize float64 `json:"font_size"` // 字体大小，可用于区分标题与正文
+}
+
+// AccessibilityInfo 合成图片的无障碍描述，可作为JSON旁路文件随图片发布
+type AccessibilityInfo struct {
+	Width   int                 `json:"width"`    // 画布宽度
+	Height  int                 `json:"height"`   // 画布高度
+	AltText string              `json:"alt_text"` // 按阅读顺序拼接的替代文本
+	Texts   []AccessibilityText `json:"texts"`    // 按阅读顺序排列的文本
+}
+
+// Accessibility 根据文本元素和圆弧文本生成无障碍描述，富文本按片段拼接为一段
+// 阅读顺序为从上到下、同一行内从左到右，Y坐标相差不超过半个字号的文本视为同一行；圆弧文本以圆心为位置
+func (ic *ImageCombiner) Accessibility() AccessibilityInfo {
+	var texts []AccessibilityText
+	for _, element := range ic.elements {
+		text := plainText(element)
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		switch e := element.(type) {
+		case *TextElement:
+			texts = append(texts, AccessibilityText{Text: text, X: e.X, Y: e.Y, FontSize: e.FontSize})
+		case *ArcTextElement:
+			texts = append(texts, AccessibilityText{Text: text, X: e.CenterX, Y: e.CenterY, FontSize: e.FontSize})
+		}
+	}
+	sort.SliceStable(texts, func(i, j int) bool { return texts[i].Y < texts[j].Y })
+
+	// 按行分组，行内按X排序
+	var ordered []AccessibilityText
+	for start := 0; start < len(texts); {
+		end := start + 1
+		for end < len(texts) && float64(texts[end].Y-texts[start].Y) <= texts[start].FontSize/2 {
+			end++
+		}
+		row := texts[start:end]
+		sort.SliceStable(row, func(i, j int) bool { return row[i].X < row[j].X })
+		ordered = append(ordered, row...)
+		start = end
+	}
+
+	info := AccessibilityInfo{Width: ic.width, Height: ic.height, Texts: []AccessibilityText{}}
+	parts := make([]string, 0, len(ordered))
+	for i, text := range ordered {
+		text.Order = i + 1
+		info.Texts = append(info.Texts, text)
+		parts = append(parts, strings.TrimSpace(text.Text))
+	}
+	info.AltText = strings.Join(parts, " ")
+	return info
+}
+
+// SaveAccessibility 将无障碍描述以JSON格式保存到文件
+func (ic *ImageCombiner) SaveAccessibility(filePath string) error {
+	data, err := json.MarshalIndent(ic.Accessibility(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
@@ -0,0 +1,34 @@
+package imgcombine
+
+import (
+	"testing"
+)
+
+// TestAccessibility 测试无障碍描述的阅读顺序
+func TestAccessibility(t *testing.T) {
+	combiner := NewImageCombiner(600, 400)
+
+	// 故意打乱添加顺序
+	combiner.AddTextElement("￥999", 40, 300, 202)
+	combiner.AddTextElement("最爱的家居", 50, 50, 80)
+	combiner.AddTextElement("￥1290", 40, 50, 200)
+	combiner.AddTextElement("扫码购买", 24, 50, 350)
+	combiner.AddTextElement("  ", 24, 0, 0)
+	spans := combiner.AddTextElement("", 24, 300, 350)
+	spans.Spans = []TextSpan{{Text: "限时"}, {Text: "包邮"}}
+	combiner.AddArcTextElement("品质之选", 20, 500, 80, 60)
+
+	info := combiner.Accessibility()
+	want := []string{"最爱的家居", "品质之选", "￥1290", "￥999", "扫码购买", "限时包邮"}
+	if len(info.Texts) != len(want) {
+		t.Fatalf("文本数量错误: got %d want %d", len(info.Texts), len(want))
+	}
+	for i, text := range want {
+		if info.Texts[i].Text != text || info.Texts[i].Order != i+1 {
+			t.Errorf("第%d段文本错误: got %+v want %s", i+1, info.Texts[i], text)
+		}
+	}
+	if info.AltText != "最爱的家居 品质之选 ￥1290 ￥999 扫码购买 限时包邮" {
+		t.Errorf("替代文本错误: %s", info.AltText)
+	}
+}
@@ -0,0 +1,168 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// adjusted 判断是否设置了任何颜色调整
+func (ie *ImageElement) adjusted() bool {
+	return ie.Grayscale || ie.Sepia || ie.Brightness != 0 || ie.Contrast != 0 || ie.Saturation != 0
+}
+
+// applyAdjustments 依次应用饱和度（灰度）、怀旧、亮度和对比度调整
+// 在非预乘透明度的颜色上计算，透明度保持不变
+func (ie *ImageElement) applyAdjustments(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	saturation := 1 + ie.Saturation
+	if ie.Grayscale {
+		saturation = 0
+	}
+	contrast := 1 + ie.Contrast
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := float64(rgba.Pix[i+3])
+		if a == 0 {
+			continue
+		}
+		r := float64(rgba.Pix[i]) * 255 / a
+		g := float64(rgba.Pix[i+1]) * 255 / a
+		b := float64(rgba.Pix[i+2]) * 255 / a
+
+		// 饱和度：在灰度与原色之间插值（或外推）
+		gray := 0.299*r + 0.587*g + 0.114*b
+		r, g, b = gray+(r-gray)*saturation, gray+(g-gray)*saturation, gray+(b-gray)*saturation
+
+		if ie.Sepia {
+			r, g, b = 0.393*r+0.769*g+0.189*b, 0.349*r+0.686*g+0.168*b, 0.272*r+0.534*g+0.131*b
+		}
+
+		// 亮度为整体偏移，对比度以中灰为中心缩放
+		shift := ie.Brightness * 255
+		r = (r+shift-128)*contrast + 128
+		g = (g+shift-128)*contrast + 128
+		b = (b+shift-128)*contrast + 128
+
+		rgba.Pix[i] = clampChannel(r * a / 255)
+		rgba.Pix[i+1] = clampChannel(g * a / 255)
+		rgba.Pix[i+2] = clampChannel(b * a / 255)
+	}
+	return rgba
+}
+
+// applyTint 将图片颜色向着色颜色混合，透明度保持不变，amount为1时图片变为单色剪影
+// 用于将单色图标渲染为任意颜色
+func applyTint(img image.Image, tint color.Color, amount float64) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	c := color.NRGBAModel.Convert(tint).(color.NRGBA)
+	target := [3]float64{float64(c.R), float64(c.G), float64(c.B)}
+	amount = math.Max(0, math.Min(1, amount)) * float64(c.A) / 255
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := float64(rgba.Pix[i+3])
+		if a == 0 {
+			continue
+		}
+		for ch := 0; ch < 3; ch++ {
+			v := float64(rgba.Pix[i+ch]) * 255 / a
+			rgba.Pix[i+ch] = clampChannel((v + (target[ch]-v)*amount) * a / 255)
+		}
+	}
+	return rgba
+}
+
+// clampChannel 将颜色分量四舍五入并限制在0-255
+func clampChannel(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
+
+// Histogram 图片各颜色通道的直方图，依次为R、G、B，完全透明的像素不计入
+type Histogram [3][256]int
+
+// NewHistogram 统计图片的颜色直方图
+func NewHistogram(img image.Image) *Histogram {
+	var h Histogram
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			h[0][c.R]++
+			h[1][c.G]++
+			h[2][c.B]++
+		}
+	}
+	return &h
+}
+
+// Levels 返回通道ch裁剪两端clip百分比像素后的最暗和最亮色阶
+func (h *Histogram) Levels(ch int, clip float64) (low, high uint8) {
+	total := 0
+	for _, n := range h[ch] {
+		total += n
+	}
+	limit := int(float64(total) * clip / 100)
+
+	lo, sum := 0, 0
+	for ; lo < 255; lo++ {
+		if sum += h[ch][lo]; sum > limit {
+			break
+		}
+	}
+	hi, sum := 255, 0
+	for ; hi > 0; hi-- {
+		if sum += h[ch][hi]; sum > limit {
+			break
+		}
+	}
+	return uint8(lo), uint8(hi)
+}
+
+// applyAutoLevels 自动色阶：按直方图将各通道的有效范围拉伸到0-255，同时校正偏色
+// clip为两端各忽略的像素百分比，用于排除少量噪点和高光
+func applyAutoLevels(img image.Image, clip float64) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	// 各通道的映射表，色阶范围过窄（如纯色图片）时不拉伸
+	hist := NewHistogram(rgba)
+	var lut [3][256]float64
+	for ch := range lut {
+		low, high := hist.Levels(ch, clip)
+		for v := range lut[ch] {
+			if high <= low {
+				lut[ch][v] = float64(v)
+			} else {
+				lut[ch][v] = (float64(v) - float64(low)) * 255 / float64(high-low)
+			}
+		}
+	}
+
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := float64(rgba.Pix[i+3])
+		if a == 0 {
+			continue
+		}
+		for ch := 0; ch < 3; ch++ {
+			v := clampChannel(float64(rgba.Pix[i+ch]) * 255 / a)
+			rgba.Pix[i+ch] = clampChannel(math.Max(0, math.Min(255, lut[ch][v])) * a / 255)
+		}
+	}
+	return rgba
+}
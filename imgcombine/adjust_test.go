@@ -0,0 +1,98 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestColorAdjustments 测试各颜色调整对纯色图片的效果
+func TestColorAdjustments(t *testing.T) {
+	base := color.RGBA{200, 100, 50, 255}
+	render := func(configure func(ie *ImageElement)) [3]uint32 {
+		combiner := NewImageCombiner(10, 10)
+		element := &ImageElement{image: solidImage(10, 10, base), ZoomMode: Origin, Alpha: 255}
+		configure(element)
+		combiner.AddElement(element)
+		img, _ := combiner.Combine()
+		return rgb(img.At(5, 5))
+	}
+
+	if c := render(func(ie *ImageElement) {}); c != [3]uint32{200, 100, 50} {
+		t.Errorf("未调整时应保持原色，实际 %v", c)
+	}
+	if c := render(func(ie *ImageElement) { ie.Grayscale = true }); c[0] != c[1] || c[1] != c[2] {
+		t.Errorf("灰度后各通道应相等，实际 %v", c)
+	}
+	if c := render(func(ie *ImageElement) { ie.Saturation = -1 }); c[0] != c[1] || c[1] != c[2] {
+		t.Errorf("饱和度-1应为灰度，实际 %v", c)
+	}
+	if c := render(func(ie *ImageElement) { ie.Sepia = true }); !(c[0] > c[1] && c[1] > c[2]) {
+		t.Errorf("怀旧应呈棕褐色，实际 %v", c)
+	}
+	if c := render(func(ie *ImageElement) { ie.Brightness = 0.2 }); c != [3]uint32{251, 151, 101} {
+		t.Errorf("亮度+0.2应整体提高51，实际 %v", c)
+	}
+	if c := render(func(ie *ImageElement) { ie.Contrast = -1 }); c != [3]uint32{128, 128, 128} {
+		t.Errorf("对比度-1应为中灰，实际 %v", c)
+	}
+	if c := render(func(ie *ImageElement) { ie.Contrast = 0.5 }); c[0] != 236 || c[2] < 10 || c[2] > 12 {
+		t.Errorf("提高对比度应拉开明暗，实际 %v", c)
+	}
+}
+
+// TestAutoLevels 测试自动色阶拉伸灰暗图片并按百分比忽略极端像素
+func TestAutoLevels(t *testing.T) {
+	// 100个像素在80到179之间均匀分布，另有1个纯白噪点
+	photo := image.NewRGBA(image.Rect(0, 0, 101, 1))
+	for x := 0; x < 100; x++ {
+		v := uint8(80 + x)
+		photo.Set(x, 0, color.RGBA{v, v, v, 255})
+	}
+	photo.Set(100, 0, color.RGBA{255, 255, 255, 255})
+
+	low, high := NewHistogram(photo).Levels(0, 0)
+	if low != 80 || high != 255 {
+		t.Errorf("未裁剪时色阶范围应为80-255，实际 %d-%d", low, high)
+	}
+	low, high = NewHistogram(photo).Levels(0, 2)
+	if low != 82 || high != 178 {
+		t.Errorf("裁剪2%%时色阶范围应为82-178，实际 %d-%d", low, high)
+	}
+
+	leveled := applyAutoLevels(photo, 2)
+	if c := leveled.RGBAAt(0, 0); c.R != 0 {
+		t.Errorf("最暗像素应拉伸为黑色，实际 %v", c)
+	}
+	if c := leveled.RGBAAt(99, 0); c.R != 255 {
+		t.Errorf("最亮像素应拉伸为白色，实际 %v", c)
+	}
+	if c := leveled.RGBAAt(50, 0); c.R < 120 || c.R > 135 {
+		t.Errorf("中间像素应拉伸到中灰附近，实际 %v", c)
+	}
+
+	// 纯色图片保持不变
+	if c := applyAutoLevels(solidImage(4, 4, color.RGBA{100, 120, 140, 255}), 1).RGBAAt(1, 1); c != (color.RGBA{100, 120, 140, 255}) {
+		t.Errorf("纯色图片不应被拉伸，实际 %v", c)
+	}
+}
+
+// TestTint 测试着色颜色和强度，透明区域保持透明
+func TestTint(t *testing.T) {
+	icon := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	icon.Set(0, 0, color.White)
+	render := func(amount float64) [2][3]uint32 {
+		combiner := NewImageCombiner(2, 1)
+		combiner.AddRectangleElement(0, 0, 2, 1).Color = color.RGBA{0, 0, 0, 255}
+		combiner.AddElement(&ImageElement{image: icon, ZoomMode: Origin, Alpha: 255, TintColor: color.RGBA{255, 0, 0, 255}, TintAmount: amount})
+		img, _ := combiner.Combine()
+		return [2][3]uint32{rgb(img.At(0, 0)), rgb(img.At(1, 0))}
+	}
+
+	if c := render(0); c[0] != [3]uint32{255, 0, 0} || c[1] != [3]uint32{0, 0, 0} {
+		t.Errorf("默认应完全着色且透明区域不变，实际 %v", c)
+	}
+	if c := render(0.5); c[0] != [3]uint32{255, 128, 128} {
+		t.Errorf("强度0.5应为白色与红色的中间色，实际 %v", c)
+	}
+}
@@ -0,0 +1,66 @@
+package imgcombine
+
+// TextAnchor 文本定位锚点，指定X/Y对应文本块外接矩形上的哪个参考点
+type TextAnchor string
+
+const (
+	AnchorBaselineLeft   TextAnchor = ""                // 首行基线左端（默认）
+	AnchorBaselineCenter TextAnchor = "baseline-center" // 首行基线中点
+	AnchorBaselineRight  TextAnchor = "baseline-right"  // 首行基线右端
+	AnchorTopLeft        TextAnchor = "top-left"        // 左上角
+	AnchorTop            TextAnchor = "top"             // 上边中点
+	AnchorTopRight       TextAnchor = "top-right"       // 右上角
+	AnchorLeft           TextAnchor = "left"            // 左边中点
+	AnchorCenter         TextAnchor = "center"          // 中心
+	AnchorRight          TextAnchor = "right"           // 右边中点
+	AnchorBottomLeft     TextAnchor = "bottom-left"     // 左下角
+	AnchorBottom         TextAnchor = "bottom"          // 下边中点
+	AnchorBottomRight    TextAnchor = "bottom-right"    // 右下角
+)
+
+// anchorFactors 返回锚点在外接矩形中的相对位置，ay为负数表示首行基线
+func (a TextAnchor) anchorFactors() (ax, ay float64) {
+	switch a {
+	case AnchorBaselineCenter:
+		return 0.5, -1
+	case AnchorBaselineRight:
+		return 1, -1
+	case AnchorTopLeft:
+		return 0, 0
+	case AnchorTop:
+		return 0.5, 0
+	case AnchorTopRight:
+		return 1, 0
+	case AnchorLeft:
+		return 0, 0.5
+	case AnchorCenter:
+		return 0.5, 0.5
+	case AnchorRight:
+		return 1, 0.5
+	case AnchorBottomLeft:
+		return 0, 1
+	case AnchorBottom:
+		return 0.5, 1
+	case AnchorBottomRight:
+		return 1, 1
+	default:
+		return 0, -1
+	}
+}
+
+// anchorOffset 计算使锚点落在(X, Y)所需的平移量，m为未平移时的测量结果
+// 水平方向以排版后的文本块为准，因此对换行、对齐和竖排同样有效
+func (te *TextElement) anchorOffset(m TextMetrics) (dx, dy float64) {
+	if te.Anchor == AnchorBaselineLeft {
+		return 0, 0
+	}
+	ax, ay := te.Anchor.anchorFactors()
+	dx = float64(te.X) - (m.Left + m.Width*ax)
+	if ay >= 0 {
+		dy = float64(te.Y) - (m.Top + m.Height*ay)
+	} else if te.Vertical {
+		// 竖排没有基线，以顶边代替
+		dy = float64(te.Y) - m.Top
+	}
+	return dx, dy
+}
@@ -0,0 +1,174 @@
+package imgcombine
+
+import (
+	"image"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// AnimationPreset 入场动画预设枚举
+type AnimationPreset string
+
+const (
+	FadeIn     AnimationPreset = "fade-in"    // 淡入
+	SlideUp    AnimationPreset = "slide-up"   // 自下方滑入并淡入
+	ZoomIn     AnimationPreset = "zoom"       // 从中心放大并淡入
+	Typewriter AnimationPreset = "typewriter" // 逐字出现，仅用于文本元素，其他元素按淡入处理
+)
+
+// defaultSlideDistance 滑入动画的默认位移(像素)
+const defaultSlideDistance = 40
+
+// Animation 元素的入场动画，由SaveFrames或WriteFrames逐帧渲染
+// 动画开始前元素不可见，结束后按原样绘制；非逐帧合成时元素始终按原样绘制
+type Animation struct {
+	Preset   AnimationPreset // 动画预设
+	Delay    int             // 开始前等待的帧数
+	Duration int             // 持续帧数
+	Distance float64         // 滑入位移(像素)，为0时使用默认值40
+	element  CombineElement
+}
+
+// Animate 为元素设置入场动画，同一元素重复设置时覆盖之前的动画
+func (ic *ImageCombiner) Animate(element CombineElement, preset AnimationPreset, delay, duration int) *Animation {
+	animation := &Animation{Preset: preset, Delay: delay, Duration: duration, element: element}
+	for i, a := range ic.animations {
+		if a.element == element {
+			ic.animations[i] = animation
+			return animation
+		}
+	}
+	ic.animations = append(ic.animations, animation)
+	return animation
+}
+
+// Stagger 为多个元素依次设置相同的入场动画，每个元素比前一个晚step帧开始
+func (ic *ImageCombiner) Stagger(preset AnimationPreset, delay, duration, step int, elements ...CombineElement) []*Animation {
+	animations := make([]*Animation, len(elements))
+	for i, element := range elements {
+		animations[i] = ic.Animate(element, preset, delay+i*step, duration)
+	}
+	return animations
+}
+
+// AnimationFrames 返回播放完所有入场动画所需的帧数，可直接作为SaveFrames的帧数
+func (ic *ImageCombiner) AnimationFrames() int {
+	frames := 0
+	for _, a := range ic.animations {
+		frames = max(frames, a.Delay+a.Duration+1)
+	}
+	return frames
+}
+
+// animation 返回元素的入场动画，未设置时返回nil
+func (ic *ImageCombiner) animation(element CombineElement) *Animation {
+	for _, a := range ic.animations {
+		if a.element == element {
+			return a
+		}
+	}
+	return nil
+}
+
+// progress 返回第frame帧的动画进度(0-1)
+func (a *Animation) progress(frame int) float64 {
+	if a.Duration <= 0 {
+		if frame < a.Delay {
+			return 0
+		}
+		return 1
+	}
+	return math.Max(0, math.Min(1, float64(frame-a.Delay)/float64(a.Duration)))
+}
+
+// draw 按第frame帧的进度绘制元素，动画已结束时返回false，由调用方按原样绘制
+func (a *Animation) draw(g *gg.Context, element CombineElement, frame, canvasWidth int) bool {
+	p := a.progress(frame)
+	if p >= 1 {
+		return false
+	}
+	if p <= 0 {
+		return true
+	}
+
+	if te, ok := element.(*TextElement); ok && a.Preset == Typewriter {
+		typed(te, p).Draw(g, canvasWidth)
+		return true
+	}
+
+	// 其余预设先绘制到透明图层，再按缓出进度设置透明度和变换
+	eased := 1 - math.Pow(1-p, 3)
+	scratch := getLayer(g.Width(), g.Height())
+	defer putLayer(scratch)
+	element.Draw(gg.NewContextForRGBA(scratch), canvasWidth)
+	fadeLayer(scratch, eased)
+
+	g.Push()
+	defer g.Pop()
+	switch a.Preset {
+	case SlideUp:
+		distance := a.Distance
+		if distance == 0 {
+			distance = defaultSlideDistance
+		}
+		g.Translate(0, (1-eased)*distance)
+	case ZoomIn:
+		if bounds := layerBounds(scratch); !bounds.Empty() {
+			cx := float64(bounds.Min.X+bounds.Max.X) / 2
+			cy := float64(bounds.Min.Y+bounds.Max.Y) / 2
+			g.ScaleAbout(eased, eased, cx, cy)
+		}
+	}
+	g.DrawImage(scratch, 0, 0)
+	return true
+}
+
+// typed 返回只保留前p比例字符的文本元素副本，富文本片段按顺序截取
+func typed(te *TextElement, p float64) *TextElement {
+	out := *te
+	if len(te.Spans) == 0 {
+		runes := []rune(te.Text)
+		out.Text = string(runes[:int(float64(len(runes))*p)])
+		return &out
+	}
+
+	total := 0
+	for _, span := range te.Spans {
+		total += len([]rune(span.Text))
+	}
+	remaining := int(float64(total) * p)
+	out.Spans = nil
+	for _, span := range te.Spans {
+		if remaining <= 0 {
+			break
+		}
+		runes := []rune(span.Text)
+		if len(runes) > remaining {
+			span.Text = string(runes[:remaining])
+		}
+		remaining -= len(runes)
+		out.Spans = append(out.Spans, span)
+	}
+	return &out
+}
+
+// fadeLayer 按opacity缩放预乘透明度图层的所有通道
+func fadeLayer(img *image.RGBA, opacity float64) {
+	for i, v := range img.Pix {
+		img.Pix[i] = uint8(float64(v) * opacity)
+	}
+}
+
+// layerBounds 返回图层中非透明像素的外接矩形
+func layerBounds(img *image.RGBA) image.Rectangle {
+	var bounds image.Rectangle
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] != 0 {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
@@ -0,0 +1,96 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// TestAnimationPresets 测试入场动画按延迟和时长逐帧推进
+func TestAnimationPresets(t *testing.T) {
+	combiner := NewImageCombiner(100, 60)
+	first := combiner.AddRectangleElement(0, 0, 40, 20)
+	first.Color = color.RGBA{255, 0, 0, 255}
+	second := combiner.AddRectangleElement(50, 20, 40, 20)
+	second.Color = color.RGBA{0, 0, 255, 255}
+	combiner.Stagger(FadeIn, 1, 2, 2, first, second)
+	slide := combiner.AddRectangleElement(0, 40, 40, 20)
+	slide.Color = color.RGBA{0, 0, 0, 255}
+	combiner.Animate(slide, SlideUp, 0, 4).Distance = 10
+
+	if n := combiner.AnimationFrames(); n != 6 {
+		t.Fatalf("动画帧数应为6，实际 %d", n)
+	}
+
+	var buf bytes.Buffer
+	if err := combiner.WriteFrames(&buf, combiner.AnimationFrames(), nil); err != nil {
+		t.Fatal(err)
+	}
+	frames := make([]image.Image, 6)
+	for i := range frames {
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames[i] = img
+	}
+
+	if c := rgb(frames[0].At(20, 10)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("延迟期间元素应不可见，实际 %v", c)
+	}
+	if c := rgb(frames[2].At(20, 10)); c[1] == 255 || c[1] == 0 {
+		t.Errorf("动画中途元素应半透明，实际 %v", c)
+	}
+	if c := rgb(frames[2].At(70, 30)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("后一个元素应错开开始，实际 %v", c)
+	}
+	if c := rgb(frames[5].At(70, 30)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("动画结束后元素应完整绘制，实际 %v", c)
+	}
+	if c := rgb(frames[1].At(20, 42)); c[0] < 200 {
+		t.Errorf("滑入中途元素应位于下方，实际 %v", c)
+	}
+
+	// 非逐帧合成时动画不生效
+	img, _ := combiner.Combine()
+	if c := rgb(img.At(20, 10)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("静态合成应完整绘制元素，实际 %v", c)
+	}
+}
+
+// TestTypewriterAnimation 测试打字机动画逐字显示文本
+func TestTypewriterAnimation(t *testing.T) {
+	combiner := NewImageCombiner(300, 60)
+	text := combiner.AddTextElement("ABCDEFGH", 24, 10, 40)
+	text.Color = color.Black
+	combiner.Animate(text, Typewriter, 0, 4)
+
+	var buf bytes.Buffer
+	if err := combiner.WriteFrames(&buf, 5, nil); err != nil {
+		t.Fatal(err)
+	}
+	var widths []int
+	for i := 0; i < 5; i++ {
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		minX, maxX, ok := inkBounds(img, img.Bounds())
+		if !ok {
+			widths = append(widths, 0)
+			continue
+		}
+		widths = append(widths, maxX-minX)
+	}
+	if widths[0] != 0 {
+		t.Errorf("第0帧不应显示文字，宽度 %d", widths[0])
+	}
+	for i := 1; i < 5; i++ {
+		if widths[i] <= widths[i-1] {
+			t.Errorf("文字宽度应逐帧增加: %v", widths)
+			break
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package imgcombine
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/fogleman/gg"
+)
+
+// StepElement 步骤编号：实心圆内居中显示数字，用于教程截图标注操作顺序
+type StepElement struct {
+	Number    int         // 编号
+	X, Y      int         // 圆心坐标
+	Radius    float64     // 半径
+	Color     color.Color // 圆的颜色
+	TextColor color.Color // 数字颜色
+	FontPaths []string    // 字体路径列表
+}
+
+// AddStepElement 添加步骤编号，默认为红底白字
+func (ic *ImageCombiner) AddStepElement(number, x, y int, radius float64) *StepElement {
+	element := &StepElement{
+		Number:    number,
+		X:         x,
+		Y:         y,
+		Radius:    radius,
+		Color:     color.RGBA{245, 34, 45, 255},
+		TextColor: color.White,
+		FontPaths: ic.FontPaths,
+	}
+
+	ic.AddElement(element)
+	return element
+}
+
+// Draw 实现CombineElement接口
+func (se *StepElement) Draw(g *gg.Context, canvasWidth int) {
+	g.Push()
+	defer g.Pop()
+
+	g.SetColor(se.Color)
+	g.DrawCircle(float64(se.X), float64(se.Y), se.Radius)
+	g.Fill()
+
+	loadFontFace(g, se.FontPaths, se.Radius)
+	g.SetColor(se.TextColor)
+	g.DrawStringAnchored(strconv.Itoa(se.Number), float64(se.X), float64(se.Y), 0.5, 0.35)
+}
+
+// ArrowElement 箭头标注：从起点指向终点，起点处可附带说明文字
+type ArrowElement struct {
+	FromX, FromY int         // 起点坐标
+	ToX, ToY     int         // 终点（箭头尖端）坐标
+	Color        color.Color // 颜色
+	Width        float64     // 线宽
+	HeadSize     float64     // 箭头长度，0表示线宽的4倍
+	Label        string      // 起点处的说明文字，位于箭头反方向
+	FontSize     float64     // 说明文字字号
+	FontPaths    []string    // 字体路径列表
+}
+
+// AddArrowElement 添加箭头标注，默认为红色
+func (ic *ImageCombiner) AddArrowElement(fromX, fromY, toX, toY int) *ArrowElement {
+	element := &ArrowElement{
+		FromX:     fromX,
+		FromY:     fromY,
+		ToX:       toX,
+		ToY:       toY,
+		Color:     color.RGBA{245, 34, 45, 255},
+		Width:     4,
+		FontSize:  24,
+		FontPaths: ic.FontPaths,
+	}
+
+	ic.AddElement(element)
+	return element
+}
+
+// Draw 实现CombineElement接口
+func (ae *ArrowElement) Draw(g *gg.Context, canvasWidth int) {
+	fx, fy, tx, ty := float64(ae.FromX), float64(ae.FromY), float64(ae.ToX), float64(ae.ToY)
+	length := math.Hypot(tx-fx, ty-fy)
+	if length == 0 {
+		return
+	}
+	dx, dy := (tx-fx)/length, (ty-fy)/length
+	head := ae.HeadSize
+	if head <= 0 {
+		head = ae.Width * 4
+	}
+	head = math.Min(head, length)
+
+	g.Push()
+	defer g.Pop()
+	g.SetColor(ae.Color)
+
+	// 线段止于箭头底边，避免粗线头超出尖端
+	g.SetLineWidth(ae.Width)
+	g.SetLineCap(gg.LineCapRound)
+	g.DrawLine(fx, fy, tx-dx*head, ty-dy*head)
+	g.Stroke()
+
+	half := head * 0.6
+	g.MoveTo(tx, ty)
+	g.LineTo(tx-dx*head-dy*half, ty-dy*head+dx*half)
+	g.LineTo(tx-dx*head+dy*half, ty-dy*head-dx*half)
+	g.ClosePath()
+	g.Fill()
+
+	if ae.Label != "" {
+		// 文字放在起点外侧：箭头向右时文字右对齐于起点，向下时文字位于起点上方
+		loadFontFace(g, ae.FontPaths, ae.FontSize)
+		gap := ae.Width + ae.FontSize/4
+		g.DrawStringAnchored(ae.Label, fx-dx*gap, fy-dy*gap, (1+dx)/2, (1-dy)/2)
+	}
+}
+
+// HighlightElement 高亮框：半透明填充和描边突出区域，可模糊区域以外的已绘制内容
+type HighlightElement struct {
+	X, Y          int         // 左上角坐标
+	Width, Height int         // 尺寸
+	Color         color.Color // 填充颜色，通常为半透明
+	BorderColor   color.Color // 描边颜色，为nil时不描边
+	BorderWidth   float64     // 描边宽度
+	RoundCorner   int         // 圆角半径
+	BlurOutside   float64     // 区域外模糊半径，0不模糊
+}
+
+// AddHighlightElement 添加高亮框，默认为半透明黄色填充和橙色描边
+func (ic *ImageCombiner) AddHighlightElement(x, y, width, height int) *HighlightElement {
+	element := &HighlightElement{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Height:      height,
+		Color:       color.NRGBA{255, 220, 0, 60},
+		BorderColor: color.RGBA{250, 140, 22, 255},
+		BorderWidth: 3,
+		RoundCorner: 6,
+	}
+
+	ic.AddElement(element)
+	return element
+}
+
+// Draw 实现CombineElement接口
+func (he *HighlightElement) Draw(g *gg.Context, canvasWidth int) {
+	x, y, w, h, r := float64(he.X), float64(he.Y), float64(he.Width), float64(he.Height), float64(he.RoundCorner)
+
+	if he.BlurOutside > 0 {
+		// 模糊当前画面，在临时图层上挖去高亮区域后覆盖回画布
+		// gg的Pop不会恢复遮罩，因此不直接在画布上裁剪
+		blurred := CurrentAccelerator().Blur(g.Image(), he.BlurOutside)
+		scratch := getLayer(g.Width(), g.Height())
+		layer := gg.NewContextForRGBA(scratch)
+		layer.DrawRoundedRectangle(x, y, w, h, r)
+		layer.Clip()
+		layer.InvertMask()
+		layer.DrawImage(blurred, 0, 0)
+		g.DrawImage(scratch, 0, 0)
+		putLayer(scratch)
+	}
+
+	g.Push()
+	defer g.Pop()
+	g.DrawRoundedRectangle(x, y, w, h, r)
+	if he.Color != nil {
+		g.SetColor(he.Color)
+		g.FillPreserve()
+	}
+	if he.BorderColor != nil && he.BorderWidth > 0 {
+		g.SetColor(he.BorderColor)
+		g.SetLineWidth(he.BorderWidth)
+		g.StrokePreserve()
+	}
+	g.ClearPath()
+}
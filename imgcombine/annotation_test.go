@@ -0,0 +1,67 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image/color"
+	"testing"
+)
+
+// TestAnnotations 测试步骤编号、箭头和高亮框的绘制
+func TestAnnotations(t *testing.T) {
+	combiner := NewImageCombiner(200, 100)
+	combiner.AddElement(&RectangleElement{X: 0, Y: 0, Width: 100, Height: 100, Color: color.RGBA{0, 0, 255, 255}})
+	combiner.AddElement(&RectangleElement{X: 100, Y: 0, Width: 100, Height: 100, Color: color.RGBA{0, 255, 0, 255}})
+	highlight := combiner.AddHighlightElement(20, 20, 60, 60)
+	highlight.BlurOutside = 10
+	combiner.AddStepElement(1, 150, 20, 12)
+	combiner.AddArrowElement(110, 90, 190, 90)
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 高亮区域内保持清晰，仅叠加半透明黄色
+	if c := rgb(img.At(50, 50)); c[2] < 150 || c[0] < 40 {
+		t.Errorf("高亮区域应为叠加黄色的蓝色，实际 %v", c)
+	}
+	// 区域外蓝绿交界处被模糊
+	if c := rgb(img.At(100, 60)); c[1] == 0 || c[2] == 0 {
+		t.Errorf("高亮区域外应被模糊，实际 %v", c)
+	}
+	if c := rgb(img.At(150, 20)); c[0] < 200 && c[1] < 200 {
+		t.Errorf("步骤编号圆心应为红色或白色数字，实际 %v", c)
+	}
+	if c := rgb(img.At(140, 20)); c[0] < 200 || c[1] > 100 {
+		t.Errorf("步骤编号应为红色圆，实际 %v", c)
+	}
+	if c := rgb(img.At(188, 90)); c[0] < 200 {
+		t.Errorf("箭头尖端应为红色，实际 %v", c)
+	}
+}
+
+// TestAnnotationsJSON 测试标注元素的JSON往返
+func TestAnnotationsJSON(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	combiner.AddArrowElement(0, 0, 50, 50).Label = "点这里"
+	combiner.AddStepElement(2, 10, 10, 8)
+	combiner.AddHighlightElement(5, 5, 20, 20).BlurOutside = 4
+
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if arrow, ok := restored.elements[0].(*ArrowElement); !ok || arrow.Label != "点这里" || arrow.ToX != 50 {
+		t.Errorf("箭头未正确还原: %#v", restored.elements[0])
+	}
+	if step, ok := restored.elements[1].(*StepElement); !ok || step.Number != 2 {
+		t.Errorf("步骤编号未正确还原: %#v", restored.elements[1])
+	}
+	if hl, ok := restored.elements[2].(*HighlightElement); !ok || hl.BlurOutside != 4 {
+		t.Errorf("高亮框未正确还原: %#v", restored.elements[2])
+	}
+}
@@ -0,0 +1,87 @@
+package imgcombine
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// ArcTextElement 沿圆弧排列的文本元素，用于徽章、印章等文字环绕圆形的场景
+// 角度单位为度，0度指向3点钟方向，顺时针为正
+type ArcTextElement struct {
+	Text             string      // 文本内容
+	FontSize         float64     // 字体大小
+	Color            color.Color // 文本颜色
+	CenterX, CenterY int         // 圆心坐标
+	Radius           float64     // 文字基线所在圆的半径
+	StartAngle       float64     // 起始角度，Centered为true时为文本中点所在角度
+	Centered         bool        // 是否以StartAngle为中点对称排列
+	Counterclockwise bool        // 逆时针排列，字头朝向圆心，用于圆形底部从左到右阅读的文字
+	LetterSpacing    float64     // 字间距(像素)
+	FontPaths        []string    // 自定义字体路径列表
+}
+
+// AddArcTextElement 添加圆弧文本元素，默认以圆的正上方为中点顺时针排列
+func (ic *ImageCombiner) AddArcTextElement(text string, fontSize float64, centerX, centerY int, radius float64) *ArcTextElement {
+	element := &ArcTextElement{
+		Text:       text,
+		FontSize:   fontSize,
+		Color:      color.Black,
+		CenterX:    centerX,
+		CenterY:    centerY,
+		Radius:     radius,
+		StartAngle: -90,
+		Centered:   true,
+		FontPaths:  ic.FontPaths,
+	}
+
+	ic.AddElement(element)
+	return element
+}
+
+// Draw 实现CombineElement接口
+// 每个字符的基线中点落在圆上并沿切线方向旋转，字符占用的圆心角为其宽度除以半径
+func (ae *ArcTextElement) Draw(g *gg.Context, canvasWidth int) {
+	if ae.Radius <= 0 || ae.Text == "" {
+		return
+	}
+
+	g.Push()
+	defer g.Pop()
+
+	loadFontFace(g, ae.FontPaths, ae.FontSize)
+	g.SetColor(ae.Color)
+
+	runes := []rune(ae.Text)
+	widths := make([]float64, len(runes))
+	total := 0.0
+	for i, r := range runes {
+		widths[i], _ = g.MeasureString(string(r))
+		total += widths[i]
+	}
+	total += ae.LetterSpacing * float64(len(runes)-1)
+
+	direction := 1.0
+	if ae.Counterclockwise {
+		direction = -1
+	}
+	angle := gg.Radians(ae.StartAngle)
+	if ae.Centered {
+		angle -= direction * total / ae.Radius / 2
+	}
+
+	cx, cy := float64(ae.CenterX), float64(ae.CenterY)
+	for i, r := range runes {
+		mid := angle + direction*widths[i]/2/ae.Radius
+		x := cx + ae.Radius*math.Cos(mid)
+		y := cy + ae.Radius*math.Sin(mid)
+
+		g.Push()
+		g.RotateAbout(mid+direction*math.Pi/2, x, y)
+		g.DrawStringAnchored(string(r), x, y, 0.5, 0)
+		g.Pop()
+
+		angle += direction * (widths[i] + ae.LetterSpacing) / ae.Radius
+	}
+}
@@ -0,0 +1,42 @@
+package imgcombine
+
+import (
+	"image"
+	"testing"
+)
+
+// TestArcText 测试圆弧文本沿圆周排列
+func TestArcText(t *testing.T) {
+	combiner := NewImageCombiner(200, 200)
+	combiner.FontPaths = []string{"../Alibaba-PuHuiTi-Medium.ttf"}
+	top := combiner.AddArcTextElement("专用章专用章", 20, 100, 100, 80)
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	// 顶部居中排列：文字位于圆外侧上方，左右大致对称，不出现在下半圆
+	minX, maxX, ok := inkBounds(img, image.Rect(0, 0, 200, 40))
+	if !ok {
+		t.Fatal("圆的正上方应有文字")
+	}
+	if abs((minX+maxX)/2-100) > 6 {
+		t.Errorf("文字应以正上方为中点，实际横向范围 %d-%d", minX, maxX)
+	}
+	if _, _, ok := inkBounds(img, image.Rect(0, 100, 200, 200)); ok {
+		t.Error("文字不应出现在下半圆")
+	}
+
+	// 逆时针排列在底部，字头朝向圆心，位于圆内侧
+	top.Text = ""
+	bottom := combiner.AddArcTextElement("No.12345", 16, 100, 100, 80)
+	bottom.StartAngle = 90
+	bottom.Counterclockwise = true
+	img, _ = combiner.Combine()
+	if _, _, ok := inkBounds(img, image.Rect(0, 150, 200, 181)); !ok {
+		t.Error("圆的正下方内侧应有文字")
+	}
+	if _, _, ok := inkBounds(img, image.Rect(0, 0, 200, 100)); ok {
+		t.Error("底部文字不应出现在上半圆")
+	}
+}
@@ -0,0 +1,59 @@
+package imgcombine
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestInitials 测试名字缩写规则
+func TestInitials(t *testing.T) {
+	for name, want := range map[string]string{
+		"张三丰":          "三丰",
+		"李四":           "李四",
+		"王":            "王",
+		"john smith":   "JS",
+		"Ada":          "A",
+		"Mary Ann Lee": "ML",
+		"  ":           "",
+	} {
+		if got := Initials(name); got != want {
+			t.Errorf("Initials(%q) = %q，期望 %q", name, got, want)
+		}
+	}
+}
+
+// TestAvatarFallback 测试头像加载失败时使用首字母头像
+func TestAvatarFallback(t *testing.T) {
+	combiner := NewImageCombiner(200, 200)
+	combiner.FontPaths = []string{"../Alibaba-PuHuiTi-Medium.ttf"}
+	avatar := combiner.AddAvatarElement("/not/exist/avatar.png", "张三", 20, 20, 120)
+	if avatar.RoundCorner != 60 {
+		t.Errorf("头像默认应为圆形，圆角 %d", avatar.RoundCorner)
+	}
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := color.RGBAModel.Convert(AvatarColor("张三"))
+	if c := color.RGBAModel.Convert(img.At(40, 80)); c != want {
+		t.Errorf("头像背景应为 %v，实际 %v", want, c)
+	}
+	if c := color.RGBAModel.Convert(img.At(21, 21)); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("圆形头像外应为画布背景，实际 %v", c)
+	}
+	white := 0
+	for y := 60; y < 100; y++ {
+		for x := 40; x < 120; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r > 0xf000 && g > 0xf000 && b > 0xf000 {
+				white++
+			}
+		}
+	}
+	if white == 0 {
+		t.Error("头像中央应绘制白色缩写")
+	}
+	if AvatarColor("张三") != AvatarColor("张三") {
+		t.Error("同一名字的头像颜色应固定")
+	}
+}
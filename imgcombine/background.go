@@ -0,0 +1,96 @@
+package imgcombine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+// ErrNoBackgroundRemover 图片元素设置了RemoveBackground但合成器未配置抠图服务
+var ErrNoBackgroundRemover = errors.New("no background remover configured")
+
+// BackgroundRemover 抠图钩子，返回去除背景后的主体图片（背景透明）
+// 可接入本地模型（如ONNX推理）或远程抠图API，用于商品拼图等模板
+type BackgroundRemover interface {
+	RemoveBackground(img image.Image) (image.Image, error)
+}
+
+// BackgroundRemoverFunc 函数形式的BackgroundRemover
+type BackgroundRemoverFunc func(img image.Image) (image.Image, error)
+
+// RemoveBackground 实现BackgroundRemover接口
+func (f BackgroundRemoverFunc) RemoveBackground(img image.Image) (image.Image, error) {
+	return f(img)
+}
+
+// RemoteBackgroundRemover 调用远程抠图API：以PNG格式POST原图，响应体为抠图后的图片
+type RemoteBackgroundRemover struct {
+	URL    string            // 接口地址
+	Header map[string]string // 附加请求头，如API密钥
+	Client *http.Client      // HTTP客户端，为nil时使用http.DefaultClient
+}
+
+// RemoveBackground 实现BackgroundRemover接口
+func (r *RemoteBackgroundRemover) RemoveBackground(img image.Image) (image.Image, error) {
+	var body bytes.Buffer
+	if err := png.Encode(&body, img); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, r.URL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "image/png")
+	for key, value := range r.Header {
+		req.Header.Set(key, value)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("remove background: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return decodeImage(resp.Body)
+}
+
+// removeBackgrounds 为设置了RemoveBackground的图片元素抠图，已修饰的图片以修饰结果抠图，
+// 将带抠图结果的元素副本按原元素放入prepared；结果缓存在合成器上，图片、修饰结果或BackgroundRemover变化时重新抠图
+func (ic *ImageCombiner) removeBackgrounds(prepared map[*ImageElement]*ImageElement) error {
+	cache := ic.processCache()
+	for _, element := range ic.elements {
+		ie, ok := element.(*ImageElement)
+		if !ok || !ie.RemoveBackground || ie.image == nil {
+			continue
+		}
+		if ic.BackgroundRemover == nil {
+			return ErrNoBackgroundRemover
+		}
+		p := prepared[ie]
+		if p == nil {
+			copied := *ie
+			p = &copied
+		}
+		remover, src := ic.BackgroundRemover, p.base()
+		cutout, err := cache.get(processKey{ie, "cutout"}, src, nil, remover, func() (image.Image, error) {
+			return remover.RemoveBackground(src)
+		})
+		if err != nil {
+			return fmt.Errorf("remove background of %s: %w", ie.ImagePath, err)
+		}
+		p.cutout = cutout
+		prepared[ie] = p
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package imgcombine
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// halfRemover 模拟抠图：左半部分为主体，右半部分透明，并记录调用次数
+type halfRemover struct {
+	calls int
+}
+
+func (r *halfRemover) RemoveBackground(img image.Image) (image.Image, error) {
+	r.calls++
+	bounds := img.Bounds()
+	cutout := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Min.X+bounds.Dx()/2; x++ {
+			cutout.Set(x, y, img.At(x, y))
+		}
+	}
+	return cutout, nil
+}
+
+// TestRemoveBackground 测试抠图钩子的调用、结果缓存和未配置时的错误
+func TestRemoveBackground(t *testing.T) {
+	remover := &halfRemover{}
+	combiner := NewImageCombiner(20, 10)
+	photo := &ImageElement{image: solidImage(20, 10, color.RGBA{255, 0, 0, 255}), ZoomMode: Origin, Alpha: 255, RemoveBackground: true}
+	combiner.AddElement(photo)
+	if _, err := combiner.Combine(); !errors.Is(err, ErrNoBackgroundRemover) {
+		t.Fatalf("未配置抠图服务应返回ErrNoBackgroundRemover，实际 %v", err)
+	}
+
+	combiner.BackgroundRemover = remover
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := rgb(img.At(5, 5)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("主体应保留，实际 %v", c)
+	}
+	if c := rgb(img.At(15, 5)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("背景应被去除，实际 %v", c)
+	}
+
+	if _, err := combiner.Combine(); err != nil {
+		t.Fatal(err)
+	}
+	if remover.calls != 1 {
+		t.Errorf("重复合成不应再次抠图，调用次数 %d", remover.calls)
+	}
+	if photo.cutout != nil {
+		t.Error("抠图结果不应写入元素")
+	}
+
+	// 修饰结果变化后以新的修饰结果重新抠图
+	retoucher := &countingRetoucher{color: color.RGBA{0, 255, 0, 255}}
+	combiner.Retoucher = retoucher
+	photo.Retouch = &RetouchOptions{RedEye: true}
+	img, _ = combiner.Combine()
+	if remover.calls != 2 {
+		t.Errorf("修饰结果变化后应重新抠图，调用次数 %d", remover.calls)
+	}
+	if c := rgb(img.At(2, 5)); c != [3]uint32{0, 255, 0} {
+		t.Errorf("应以修饰结果抠图，实际 %v", c)
+	}
+	combiner.Combine()
+	if remover.calls != 2 || retoucher.calls != 1 {
+		t.Errorf("输入不变时不应重新处理，抠图 %d 次、修饰 %d 次", remover.calls, retoucher.calls)
+	}
+
+	// 替换抠图服务后重新抠图
+	combiner.BackgroundRemover = BackgroundRemoverFunc(func(img image.Image) (image.Image, error) {
+		return image.NewRGBA(img.Bounds()), nil
+	})
+	img, _ = combiner.Combine()
+	if c := rgb(img.At(5, 5)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("替换抠图服务后应使用新的抠图结果，实际 %v", c)
+	}
+}
+
+// TestRemoteBackgroundRemover 测试远程抠图API的请求与响应解析
+func TestRemoteBackgroundRemover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		img, err := png.Decode(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		png.Encode(w, image.NewNRGBA(img.Bounds()))
+	}))
+	defer server.Close()
+
+	remover := &RemoteBackgroundRemover{URL: server.URL, Header: map[string]string{"X-Api-Key": "secret"}}
+	cutout, err := remover.RemoveBackground(solidImage(4, 3, color.White))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cutout.Bounds().Dx() != 4 || cutout.Bounds().Dy() != 3 {
+		t.Errorf("抠图结果尺寸错误: %v", cutout.Bounds())
+	}
+
+	remover.Header = nil
+	if _, err := remover.RemoveBackground(solidImage(4, 3, color.White)); err == nil {
+		t.Error("接口返回错误状态时应返回错误")
+	}
+}
@@ -0,0 +1,39 @@
+package imgcombine
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+)
+
+// newDenseCombiner 构建包含大量文本和矩形的合成器，模拟词云和密集表格
+func newDenseCombiner(n int) *ImageCombiner {
+	combiner := NewImageCombiner(1000, 1000)
+	combiner.FontPaths = []string{"../Alibaba-PuHuiTi-Medium.ttf"}
+	for i := 0; i < n; i++ {
+		x, y := (i*37)%960, (i*53)%960+20
+		cell := combiner.AddRectangleElement(x, y-20, 40, 24)
+		cell.Color = color.RGBA{uint8(i), 200, 220, 255}
+		text := combiner.AddTextElement(fmt.Sprintf("词%d", i%100), float64(12+i%4*4), x, y)
+		text.Color = color.RGBA{20, 20, 20, 255}
+	}
+	return combiner
+}
+
+// BenchmarkCombineDense 测试大量元素的合成性能
+func BenchmarkCombineDense(b *testing.B) {
+	combiner := newDenseCombiner(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		combiner.Combine()
+	}
+}
+
+// BenchmarkAddElements 测试批量添加元素的开销
+func BenchmarkAddElements(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		newDenseCombiner(2000)
+	}
+}
@@ -0,0 +1,149 @@
+package imgcombine
+
+import (
+	"image"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// BlendMode 元素与画布的混合模式枚举，公式与CSS mix-blend-mode一致
+type BlendMode string
+
+const (
+	BlendNormal     BlendMode = ""            // 正常：直接覆盖
+	BlendMultiply   BlendMode = "multiply"    // 正片叠底：变暗，常用于阴影
+	BlendScreen     BlendMode = "screen"      // 滤色：变亮，常用于光晕
+	BlendOverlay    BlendMode = "overlay"     // 叠加：按底色增强对比
+	BlendDarken     BlendMode = "darken"      // 变暗：取较暗者
+	BlendLighten    BlendMode = "lighten"     // 变亮：取较亮者
+	BlendColorDodge BlendMode = "color-dodge" // 颜色减淡
+	BlendColorBurn  BlendMode = "color-burn"  // 颜色加深
+	BlendHardLight  BlendMode = "hard-light"  // 强光
+	BlendSoftLight  BlendMode = "soft-light"  // 柔光
+	BlendDifference BlendMode = "difference"  // 差值
+	BlendExclusion  BlendMode = "exclusion"   // 排除
+)
+
+// BlendedElement 以非正常混合模式合成到画布的元素
+// 合成器先将元素绘制到透明图层，再按混合模式与画布合成
+type BlendedElement interface {
+	CombineElement
+	Blend() BlendMode
+}
+
+// Blend 实现BlendedElement接口
+func (ie *ImageElement) Blend() BlendMode { return ie.BlendMode }
+
+// Blend 实现BlendedElement接口
+func (te *TextElement) Blend() BlendMode { return te.BlendMode }
+
+// Blend 实现BlendedElement接口
+func (re *RectangleElement) Blend() BlendMode { return re.BlendMode }
+
+// Blend 实现BlendedElement接口
+func (pe *PathElement) Blend() BlendMode { return pe.BlendMode }
+
+// blendFunc 返回混合模式的颜色分量混合函数，cb为画布颜色，cs为元素颜色，均为0-1
+func blendFunc(mode BlendMode) func(cb, cs float64) float64 {
+	switch mode {
+	case BlendMultiply:
+		return func(cb, cs float64) float64 { return cb * cs }
+	case BlendScreen:
+		return screen
+	case BlendOverlay:
+		return func(cb, cs float64) float64 { return hardLight(cs, cb) }
+	case BlendDarken:
+		return math.Min
+	case BlendLighten:
+		return math.Max
+	case BlendColorDodge:
+		return func(cb, cs float64) float64 {
+			switch {
+			case cb == 0:
+				return 0
+			case cs >= 1:
+				return 1
+			default:
+				return math.Min(1, cb/(1-cs))
+			}
+		}
+	case BlendColorBurn:
+		return func(cb, cs float64) float64 {
+			switch {
+			case cb >= 1:
+				return 1
+			case cs <= 0:
+				return 0
+			default:
+				return 1 - math.Min(1, (1-cb)/cs)
+			}
+		}
+	case BlendHardLight:
+		return hardLight
+	case BlendSoftLight:
+		return func(cb, cs float64) float64 {
+			if cs <= 0.5 {
+				return cb - (1-2*cs)*cb*(1-cb)
+			}
+			d := math.Sqrt(cb)
+			if cb <= 0.25 {
+				d = ((16*cb-12)*cb + 4) * cb
+			}
+			return cb + (2*cs-1)*(d-cb)
+		}
+	case BlendDifference:
+		return func(cb, cs float64) float64 { return math.Abs(cb - cs) }
+	case BlendExclusion:
+		return func(cb, cs float64) float64 { return cb + cs - 2*cb*cs }
+	default:
+		return func(cb, cs float64) float64 { return cs }
+	}
+}
+
+func screen(cb, cs float64) float64 {
+	return cb + cs - cb*cs
+}
+
+func hardLight(cb, cs float64) float64 {
+	if cs <= 0.5 {
+		return cb * 2 * cs
+	}
+	return screen(cb, 2*cs-1)
+}
+
+// drawBlended 将元素绘制到临时图层后按混合模式合成到画布
+func drawBlended(ctx *gg.Context, element CombineElement, mode BlendMode, canvasWidth int) {
+	dst, ok := ctx.Image().(*image.RGBA)
+	if !ok {
+		element.Draw(ctx, canvasWidth)
+		return
+	}
+	scratch := getLayer(dst.Rect.Dx(), dst.Rect.Dy())
+	element.Draw(gg.NewContextForRGBA(scratch), canvasWidth)
+	blendLayer(dst, scratch, mode)
+	putLayer(scratch)
+}
+
+// blendLayer 按混合模式将src合成到dst，两者尺寸相同且均为预乘透明度
+// 结果颜色为 (1-αb)·Cs + αb·B(Cb, Cs)，再按源覆盖方式合成
+func blendLayer(dst, src *image.RGBA, mode BlendMode) {
+	blend := blendFunc(mode)
+	for i := 0; i < len(src.Pix); i += 4 {
+		as := float64(src.Pix[i+3]) / 255
+		if as == 0 {
+			continue
+		}
+		ab := float64(dst.Pix[i+3]) / 255
+		for c := 0; c < 3; c++ {
+			cs := float64(src.Pix[i+c]) / 255 / as
+			cb := 0.0
+			if ab > 0 {
+				cb = float64(dst.Pix[i+c]) / 255 / ab
+			}
+			mixed := (1-ab)*cs + ab*blend(cb, cs)
+			dst.Pix[i+c] = clampChannel((as*mixed + (1-as)*ab*cb) * 255)
+		}
+		dst.Pix[i+3] = clampChannel((as + ab*(1-as)) * 255)
+	}
+}
@@ -0,0 +1,62 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image/color"
+	"testing"
+)
+
+// TestBlendMode 测试元素按混合模式与画布合成
+func TestBlendMode(t *testing.T) {
+	tests := []struct {
+		mode BlendMode
+		want [3]uint32
+	}{
+		{BlendNormal, [3]uint32{100, 100, 100}},
+		{BlendMultiply, [3]uint32{78, 39, 39}},
+		{BlendScreen, [3]uint32{222, 161, 161}},
+		{BlendDarken, [3]uint32{100, 100, 100}},
+		{BlendLighten, [3]uint32{200, 100, 100}},
+		{BlendDifference, [3]uint32{100, 0, 0}},
+	}
+	for _, tt := range tests {
+		combiner := NewImageCombiner(20, 20)
+		combiner.AddRectangleElement(0, 0, 20, 20).Color = color.RGBA{200, 100, 100, 255}
+		rect := combiner.AddRectangleElement(0, 0, 10, 20)
+		rect.Color = color.RGBA{100, 100, 100, 255}
+		rect.BlendMode = tt.mode
+		img, err := combiner.Combine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := rgb(img.At(5, 10)); c != tt.want {
+			t.Errorf("%q 混合结果应为 %v，实际 %v", tt.mode, tt.want, c)
+		}
+		if c := rgb(img.At(15, 10)); c != [3]uint32{200, 100, 100} {
+			t.Errorf("%q 元素外的画布不应改变，实际 %v", tt.mode, c)
+		}
+	}
+
+	// 半透明元素按透明度与混合结果插值
+	combiner := NewImageCombiner(10, 10)
+	combiner.AddRectangleElement(0, 0, 10, 10).Color = color.RGBA{200, 200, 200, 255}
+	rect := combiner.AddRectangleElement(0, 0, 10, 10)
+	rect.Color = color.NRGBA{0, 0, 0, 128}
+	rect.BlendMode = BlendScreen
+	img, _ := combiner.Combine()
+	if c := rgb(img.At(5, 5)); c != [3]uint32{200, 200, 200} {
+		t.Errorf("黑色滤色不应改变画布，实际 %v", c)
+	}
+
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if r := restored.elements[1].(*RectangleElement); r.BlendMode != BlendScreen {
+		t.Errorf("混合模式未正确恢复: %q", r.BlendMode)
+	}
+}
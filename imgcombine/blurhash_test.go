@@ -0,0 +1,39 @@
+package imgcombine
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestBlurHash 测试纯色画布的BlurHash编码
+func TestBlurHash(t *testing.T) {
+	combiner := NewImageCombiner(300, 200)
+
+	hash, err := combiner.BlurHash(4, 3)
+	if err != nil {
+		t.Fatalf("计算BlurHash失败: %v", err)
+	}
+	// 4x3分量：尺寸标记为L，长度为1+1+4+2*11
+	if len(hash) != 28 || hash[0] != 'L' {
+		t.Errorf("BlurHash格式错误: %s", hash)
+	}
+	// 纯白画布的DC分量为白色
+	if dc := hash[2:6]; dc != encodeBase83(0xFFFFFF, 4) {
+		t.Errorf("DC分量应为白色，实际 %s", dc)
+	}
+	white := hash
+
+	rect := combiner.AddRectangleElement(0, 0, 150, 200)
+	rect.Color = color.RGBA{255, 0, 0, 255}
+	hash, err = combiner.BlurHash(4, 3)
+	if err != nil {
+		t.Fatalf("计算BlurHash失败: %v", err)
+	}
+	if len(hash) != len(white) || hash == white {
+		t.Errorf("非纯色画布的BlurHash异常: %s", hash)
+	}
+
+	if _, err := combiner.BlurHash(0, 3); err == nil {
+		t.Error("分量数超出范围应返回错误")
+	}
+}
@@ -0,0 +1,28 @@
+package imgcombine
+
+import (
+	"github.com/fogleman/gg"
+)
+
+// cornerRadii 返回按图片尺寸限制后的四角圆角半径，不小于短边一半时为圆形
+func (ie *ImageElement) cornerRadii(width, height int) [4]float64 {
+	return cornerRadii(ie.RoundCorner, ie.CornerRadii, float64(width), float64(height))
+}
+
+// drawBorder 沿图片轮廓绘制描边，设置圆角时描边同样为圆角或圆形，各角半径与图片一致
+// 描边完全位于图片区域内，不改变元素占用的尺寸
+func (ie *ImageElement) drawBorder(g *gg.Context, width, height, x, y int) {
+	if ie.BorderColor == nil || ie.BorderWidth <= 0 {
+		return
+	}
+
+	inset := ie.BorderWidth / 2
+	radii := ie.cornerRadii(width, height)
+	for i := range radii {
+		radii[i] = max(radii[i]-inset, 0)
+	}
+	drawRoundedRect(g, float64(x)+inset, float64(y)+inset, float64(width)-ie.BorderWidth, float64(height)-ie.BorderWidth, radii)
+	g.SetColor(ie.BorderColor)
+	g.SetLineWidth(ie.BorderWidth)
+	g.Stroke()
+}
@@ -0,0 +1,214 @@
+package imgcombine
+
+import (
+	"strings"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// CharPosition 单个字符的排版位置，坐标为按锚点平移后、未旋转时的画布坐标
+type CharPosition struct {
+	Rune  rune    // 字符
+	Line  int     // 所在行，竖排时为所在列
+	X, Y  float64 // 字符绘制起点（左端基线）
+	Width float64 // 字符宽度（步进宽度）
+	Size  float64 // 字号
+}
+
+// CharPositions 按与Draw相同的排版逻辑返回每个字符的位置，包含字距调整、制表位、对齐和两端对齐
+// 用于逐帧动画单独控制每个字符（如打字机、波浪效果），换行符和被截断的字符不返回
+func (te *TextElement) CharPositions() []CharPosition {
+	var positions []CharPosition
+	switch {
+	case len(te.Spans) > 0:
+		positions = te.spanCharPositions()
+	case te.Vertical:
+		positions = te.verticalCharPositions()
+	default:
+		positions = te.lineCharPositions()
+	}
+
+	if te.Anchor != AnchorBaselineLeft {
+		dx, dy := te.anchorOffset(te.measure())
+		for i := range positions {
+			positions[i].X += dx
+			positions[i].Y += dy
+		}
+	}
+	return positions
+}
+
+// textFace 返回文本元素的字体，无可用字体时使用gg默认字体
+func (te *TextElement) textFace() font.Face {
+	face, _ := te.face()
+	if face == nil {
+		return basicfont.Face7x13
+	}
+	return face
+}
+
+// lineCharPositions 计算纯文本各字符的位置
+func (te *TextElement) lineCharPositions() []CharPosition {
+	g := gg.NewContext(1, 1)
+	face := te.textFace()
+	g.SetFontFace(face)
+
+	var positions []CharPosition
+	lines, ends := te.wrapParagraphs(g)
+	for i, line := range lines {
+		x := te.lineX(te.lineWidth(g, line))
+		y := float64(te.Y) + float64(i)*te.lineHeight()
+		offsets, widths := te.charOffsets(g, face, line, te.justified(line, ends[i]))
+		for j, r := range []rune(line) {
+			positions = append(positions, CharPosition{Rune: r, Line: i, X: x + offsets[j], Y: y, Width: widths[j], Size: te.FontSize})
+		}
+	}
+	return positions
+}
+
+// charOffsets 返回一行中各字符相对于行首的偏移和宽度，逻辑与drawLine、drawJustified一致
+// 制表符的宽度为到下一个制表位的距离
+func (te *TextElement) charOffsets(g *gg.Context, face font.Face, line string, justified bool) (offsets, widths []float64) {
+	if justified {
+		if offsets, widths, ok := te.justifiedOffsets(g, face, line); ok {
+			return offsets, widths
+		}
+	}
+
+	offset := 0.0
+	for i, segment := range strings.Split(line, "\t") {
+		if i > 0 {
+			stop := te.nextTabStop(offset)
+			offsets = append(offsets, offset)
+			widths = append(widths, stop-offset)
+			offset = stop
+		}
+		o, w := kernOffsets(face, segment)
+		for k := range o {
+			offsets = append(offsets, offset+o[k])
+			widths = append(widths, w[k])
+		}
+		width, _ := g.MeasureString(segment)
+		offset += width
+	}
+	return offsets, widths
+}
+
+// justifiedOffsets 按drawJustified的拉伸规则计算各字符偏移，空格的宽度为拉伸后的间距
+// 行无法拉伸时返回false
+func (te *TextElement) justifiedOffsets(g *gg.Context, face font.Face, line string) (offsets, widths []float64, ok bool) {
+	trimmed := strings.TrimRight(line, " ")
+	natural, _ := g.MeasureString(trimmed)
+	maxWidth := float64(te.MaxLineWidth)
+
+	var parts []string
+	sep := ""
+	if strings.Contains(trimmed, " ") {
+		parts = strings.Split(trimmed, " ")
+		sep = " "
+	} else {
+		for _, r := range trimmed {
+			parts = append(parts, string(r))
+		}
+	}
+	if len(parts) < 2 || natural >= maxWidth {
+		return nil, nil, false
+	}
+
+	partsWidth := 0.0
+	for _, part := range parts {
+		w, _ := g.MeasureString(part)
+		partsWidth += w
+	}
+	sepWidth, _ := g.MeasureString(sep)
+	gap := (maxWidth - partsWidth) / float64(len(parts)-1)
+	if sep != "" {
+		gap = max(gap, sepWidth)
+	}
+
+	cursor := 0.0
+	for i, part := range parts {
+		o, w := kernOffsets(face, part)
+		for k := range o {
+			offsets = append(offsets, cursor+o[k])
+			widths = append(widths, w[k])
+		}
+		pw, _ := g.MeasureString(part)
+		cursor += pw
+		if i < len(parts)-1 {
+			if sep != "" {
+				offsets = append(offsets, cursor)
+				widths = append(widths, gap)
+			}
+			cursor += gap
+		}
+	}
+
+	// 行尾被忽略的空格位于行末
+	for range len([]rune(line)) - len(offsets) {
+		offsets = append(offsets, cursor)
+		widths = append(widths, sepWidth)
+	}
+	return offsets, widths, true
+}
+
+// kernOffsets 返回文本中各字符相对于起点的偏移和步进宽度，包含字距调整
+func kernOffsets(face font.Face, text string) (offsets, widths []float64) {
+	offset := 0.0
+	prev := rune(-1)
+	for _, r := range text {
+		if prev >= 0 {
+			offset += float64(face.Kern(prev, r)) / 64
+		}
+		advance, _ := face.GlyphAdvance(r)
+		offsets = append(offsets, offset)
+		widths = append(widths, float64(advance)/64)
+		offset += float64(advance) / 64
+		prev = r
+	}
+	return offsets, widths
+}
+
+// spanCharPositions 计算富文本各字符的位置
+func (te *TextElement) spanCharPositions() []CharPosition {
+	faces, _ := te.spanFaces()
+	lineHeight := te.lineHeight()
+
+	var positions []CharPosition
+	for i, line := range te.layoutSpans() {
+		x := te.lineX(line.width)
+		y := float64(te.Y) + float64(i)*lineHeight
+		for _, run := range line.runs {
+			// 各片段单独绘制，片段之间没有字距调整
+			text := string(run.text)
+			offsets, widths := kernOffsets(faces[run.span], text)
+			for k, r := range run.text {
+				positions = append(positions, CharPosition{Rune: r, Line: i, X: x + offsets[k], Y: y, Width: widths[k], Size: te.spanSize(run.span)})
+			}
+			x += float64(font.MeasureString(faces[run.span], text)) / 64
+		}
+	}
+	return positions
+}
+
+// verticalCharPositions 计算竖排文本各字符的位置，字符在字号大小的方格内居中
+func (te *TextElement) verticalCharPositions() []CharPosition {
+	g := gg.NewContext(1, 1)
+	face := te.textFace()
+	g.SetFontFace(face)
+	height := g.FontHeight()
+
+	var positions []CharPosition
+	for i, column := range te.verticalColumns() {
+		centerX := float64(te.X) + te.FontSize/2 - float64(i)*te.columnSpacing()
+		for j, r := range column {
+			centerY := float64(te.Y) + (float64(j)+0.5)*te.FontSize
+			advance, _ := face.GlyphAdvance(r)
+			width := float64(advance) / 64
+			positions = append(positions, CharPosition{Rune: r, Line: i, X: centerX - width/2, Y: centerY + height*0.35, Width: width, Size: te.FontSize})
+		}
+	}
+	return positions
+}
@@ -0,0 +1,69 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// ChromaKey 颜色替换（抠色）：将接近目标颜色的像素替换为透明或指定颜色
+// 常用于去除素材的纯色背景
+type ChromaKey struct {
+	Color     color.Color // 目标颜色
+	Tolerance float64     // 容差(0-1)，与目标颜色的距离不超过该值的像素被完全替换
+	Feather   float64     // 羽化宽度(0-1)，距离在容差之外该宽度内的像素部分替换，使边缘平滑
+	Replace   color.Color // 替换颜色，为nil时替换为透明
+}
+
+// NewChromaKey 创建抠色滤镜，默认容差0.1、羽化0.1，替换为透明
+func NewChromaKey(c color.Color) *ChromaKey {
+	return &ChromaKey{Color: c, Tolerance: 0.1, Feather: 0.1}
+}
+
+// keep 返回像素保留原色的比例，0为完全替换，1为完全保留
+// dist为与目标颜色在RGB空间的欧氏距离，归一化到0-1
+func (ck *ChromaKey) keep(dist float64) float64 {
+	switch {
+	case dist <= ck.Tolerance:
+		return 0
+	case dist >= ck.Tolerance+ck.Feather:
+		return 1
+	default:
+		return (dist - ck.Tolerance) / ck.Feather
+	}
+}
+
+// apply 返回替换后的图片，在原始尺寸上处理以便缩放时平滑边缘
+func (ck *ChromaKey) apply(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	key := color.NRGBAModel.Convert(ck.Color).(color.NRGBA)
+	var replace color.RGBA
+	if ck.Replace != nil {
+		replace = color.RGBAModel.Convert(ck.Replace).(color.RGBA)
+	}
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		a := float64(rgba.Pix[i+3])
+		if a == 0 {
+			continue
+		}
+		dr := float64(rgba.Pix[i])*255/a - float64(key.R)
+		dg := float64(rgba.Pix[i+1])*255/a - float64(key.G)
+		db := float64(rgba.Pix[i+2])*255/a - float64(key.B)
+		keep := ck.keep(math.Sqrt(dr*dr+dg*dg+db*db) / (255 * math.Sqrt(3)))
+		if keep == 1 {
+			continue
+		}
+
+		// 预乘透明度下按比例混合原色与替换色，替换色的透明度随原像素透明度缩放
+		scale := a / 255 * (1 - keep)
+		rgba.Pix[i] = clampChannel(float64(rgba.Pix[i])*keep + float64(replace.R)*scale)
+		rgba.Pix[i+1] = clampChannel(float64(rgba.Pix[i+1])*keep + float64(replace.G)*scale)
+		rgba.Pix[i+2] = clampChannel(float64(rgba.Pix[i+2])*keep + float64(replace.B)*scale)
+		rgba.Pix[i+3] = clampChannel(a*keep + float64(replace.A)*scale)
+	}
+	return rgba
+}
@@ -0,0 +1,40 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestChromaKey 测试抠色的容差、羽化和替换颜色
+func TestChromaKey(t *testing.T) {
+	green := color.RGBA{0, 255, 0, 255}
+	photo := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	photo.Set(0, 0, color.RGBA{10, 245, 10, 255}) // 容差内
+	photo.Set(1, 0, color.RGBA{30, 225, 30, 255}) // 羽化区域
+	photo.Set(2, 0, color.RGBA{255, 0, 0, 255})   // 保留
+
+	keyed := NewChromaKey(green).apply(photo)
+	if a := keyed.RGBAAt(0, 0).A; a != 0 {
+		t.Errorf("容差内的像素应完全透明，实际透明度 %d", a)
+	}
+	if a := keyed.RGBAAt(1, 0).A; a == 0 || a == 255 {
+		t.Errorf("羽化区域的像素应半透明，实际透明度 %d", a)
+	}
+	if c := keyed.RGBAAt(2, 0); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("远离目标颜色的像素应保持不变，实际 %v", c)
+	}
+
+	replace := NewChromaKey(green)
+	replace.Replace = color.RGBA{0, 0, 255, 255}
+	if c := replace.apply(photo).RGBAAt(0, 0); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("容差内的像素应替换为指定颜色，实际 %v", c)
+	}
+
+	combiner := NewImageCombiner(3, 1)
+	combiner.AddElement(&ImageElement{image: photo, ZoomMode: Origin, Alpha: 255, ChromaKey: NewChromaKey(green)})
+	img, _ := combiner.Combine()
+	if c := rgb(img.At(0, 0)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("抠除的像素应透出背景，实际 %v", c)
+	}
+}
@@ -0,0 +1,167 @@
+package imgcombine
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// ColorWarningKind 配色检查告警类型枚举
+type ColorWarningKind string
+
+const (
+	WarningLowContrast ColorWarningKind = "low_contrast" // 文本与背景对比度不足
+	WarningColorBlind  ColorWarningKind = "color_blind"  // 绿色弱(deuteranopia)下颜色难以区分
+)
+
+const (
+	minContrastNormal = 4.5 // WCAG AA 普通文本最低对比度
+	minContrastLarge  = 3.0 // WCAG AA 大号文本最低对比度
+	largeTextSize     = 24  // 大号文本的字号阈值(像素，约18pt)
+	minColorDistance  = 40  // 色弱模拟后两种颜色的最小可区分距离(sRGB欧氏距离)
+)
+
+// ColorWarning 配色检查告警
+type ColorWarning struct {
+	Kind    ColorWarningKind // 告警类型
+	Element CombineElement   // 相关元素
+	Ratio   float64          // 对比度（仅对比度告警）
+	Message string           // 告警说明
+}
+
+// CheckColors 检查配色可读性
+// 文本元素按WCAG标准计算与其下方背景的对比度；
+// 图表元素(环形、仪表盘)模拟绿色弱视觉，检查相邻颜色是否仍可区分
+func (ic *ImageCombiner) CheckColors() ([]ColorWarning, error) {
+	theme := ic.activeTheme()
+	ctx := gg.NewContext(ic.width, ic.height)
+	ctx.SetColor(theme.background())
+	ctx.Clear()
+
+	var warnings []ColorWarning
+	for _, element := range ic.elements {
+		// 按主题解析颜色后检查，告警仍指向原元素
+		first := len(warnings)
+		resolved := theme.resolve(theme.withDefaults(element))
+		switch e := resolved.(type) {
+		case *TextElement:
+			// 以绘制该文本之前的画面作为背景
+			if w, ok := checkTextContrast(e, ctx.Image()); ok {
+				warnings = append(warnings, w)
+			}
+		case *DonutElement:
+			warnings = append(warnings, checkColorBlind(e, e.Color, e.TrackColor)...)
+		case *GaugeElement:
+			for i := 1; i < len(e.Zones); i++ {
+				warnings = append(warnings, checkColorBlind(e, e.Zones[i-1].Color, e.Zones[i].Color)...)
+			}
+		}
+		for i := first; i < len(warnings); i++ {
+			warnings[i].Element = element
+		}
+		resolved.Draw(ctx, ic.width)
+	}
+	return warnings, nil
+}
+
+// checkTextContrast 计算文本颜色与背景平均亮度的对比度
+func checkTextContrast(te *TextElement, backdrop image.Image) (ColorWarning, bool) {
+	if plainText(te) == "" {
+		return ColorWarning{}, false
+	}
+
+	// 估算文本区域：宽度取最长行，高度从首行顶部到末行基线
+	g := gg.NewContext(1, 1)
+	te.loadFont(g)
+	lines := te.wrapLines(g)
+	lineHeight := te.lineHeight()
+	rect := image.Rect(
+		te.X,
+		te.Y-int(te.FontSize),
+		te.X+int(math.Ceil(te.GetWidth())),
+		te.Y+int(float64(len(lines)-1)*lineHeight),
+	)
+	dx, dy := te.anchorOffset(te.measure())
+	rect = rect.Add(image.Pt(int(dx), int(dy))).Intersect(backdrop.Bounds())
+	if rect.Empty() {
+		return ColorWarning{}, false
+	}
+
+	sum := 0.0
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			sum += relativeLuminance(backdrop.At(x, y))
+		}
+	}
+	background := sum / float64(rect.Dx()*rect.Dy())
+	ratio := contrastRatio(relativeLuminance(te.textColor()), background)
+
+	required := minContrastNormal
+	if te.FontSize >= largeTextSize {
+		required = minContrastLarge
+	}
+	if ratio >= required {
+		return ColorWarning{}, false
+	}
+	return ColorWarning{
+		Kind:    WarningLowContrast,
+		Element: te,
+		Ratio:   ratio,
+		Message: fmt.Sprintf("text %q contrast %.2f:1 is below %.1f:1", plainText(te), ratio, required),
+	}, true
+}
+
+// checkColorBlind 检查两种颜色在绿色弱视觉下是否可区分
+func checkColorBlind(element CombineElement, a, b color.Color) []ColorWarning {
+	if a == nil || b == nil {
+		return nil
+	}
+	sa, sb := simulateDeuteranopia(a), simulateDeuteranopia(b)
+	dr := float64(sa.R) - float64(sb.R)
+	dg := float64(sa.G) - float64(sb.G)
+	db := float64(sa.B) - float64(sb.B)
+	distance := math.Sqrt(dr*dr + dg*dg + db*db)
+	if distance >= minColorDistance {
+		return nil
+	}
+	return []ColorWarning{{
+		Kind:    WarningColorBlind,
+		Element: element,
+		Message: fmt.Sprintf("colors %v and %v are hard to distinguish with deuteranopia (distance %.1f)", rgbaOf(a), rgbaOf(b), distance),
+	}}
+}
+
+// simulateDeuteranopia 模拟绿色弱视觉下看到的颜色（Machado 2009，严重度1.0）
+func simulateDeuteranopia(c color.Color) color.RGBA {
+	r, g, b, a := c.RGBA()
+	lr, lg, lb := sRGBToLinear(r>>8), sRGBToLinear(g>>8), sRGBToLinear(b>>8)
+	return color.RGBA{
+		R: uint8(linearToSRGB(0.367322*lr + 0.860646*lg - 0.227968*lb)),
+		G: uint8(linearToSRGB(0.280085*lr + 0.672501*lg + 0.047413*lb)),
+		B: uint8(linearToSRGB(-0.011820*lr + 0.042940*lg + 0.968881*lb)),
+		A: uint8(a >> 8),
+	}
+}
+
+// relativeLuminance 计算WCAG相对亮度
+func relativeLuminance(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return 0.2126*sRGBToLinear(r>>8) + 0.7152*sRGBToLinear(g>>8) + 0.0722*sRGBToLinear(b>>8)
+}
+
+// contrastRatio 计算两个相对亮度之间的WCAG对比度
+func contrastRatio(l1, l2 float64) float64 {
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// rgbaOf 将颜色转换为color.RGBA，便于输出
+func rgbaOf(c color.Color) color.RGBA {
+	r, g, b, a := c.RGBA()
+	return color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+}
@@ -0,0 +1,65 @@
+package imgcombine
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestCheckColors 测试文本对比度与色弱检查
+func TestCheckColors(t *testing.T) {
+	combiner := NewImageCombiner(400, 300)
+
+	bg := combiner.AddRectangleElement(0, 0, 400, 150)
+	bg.Color = color.RGBA{30, 30, 30, 255}
+
+	// 深色背景上的白字：对比度充足
+	good := combiner.AddTextElement("清晰", 20, 20, 60)
+	good.Color = color.White
+	// 深色背景上的深灰字：对比度不足
+	bad := combiner.AddTextElement("模糊", 20, 20, 120)
+	bad.Color = color.RGBA{70, 70, 70, 255}
+
+	// 红绿色区在绿色弱下难以区分
+	gauge := combiner.AddGaugeElement(50, 0, 100, 250, 160, 60)
+	gauge.Zones = []GaugeZone{
+		{From: 0, To: 50, Color: color.RGBA{200, 60, 40, 255}},
+		{From: 50, To: 100, Color: color.RGBA{120, 120, 30, 255}},
+	}
+
+	warnings, err := combiner.CheckColors()
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+
+	var contrast, blind int
+	for _, w := range warnings {
+		switch w.Kind {
+		case WarningLowContrast:
+			contrast++
+			if w.Element != bad {
+				t.Errorf("对比度告警元素错误: %s", w.Message)
+			}
+			if w.Ratio >= minContrastNormal {
+				t.Errorf("告警对比度应低于阈值: %.2f", w.Ratio)
+			}
+		case WarningColorBlind:
+			blind++
+		}
+	}
+	if contrast != 1 || blind != 1 {
+		t.Errorf("告警数量错误: contrast=%d colorblind=%d %+v", contrast, blind, warnings)
+	}
+
+	if ratio := contrastRatio(relativeLuminance(color.Black), relativeLuminance(color.White)); ratio < 20.9 || ratio > 21.1 {
+		t.Errorf("黑白对比度应为21:1，实际 %.2f", ratio)
+	}
+
+	// 只有富文本片段的文本同样检查对比度
+	spans := NewImageCombiner(200, 100)
+	faint := spans.AddTextElement("", 20, 20, 60)
+	faint.Color = color.RGBA{240, 240, 240, 255}
+	faint.Spans = []TextSpan{{Text: "浅色"}, {Text: "文字"}}
+	if warnings, _ := spans.CheckColors(); len(warnings) != 1 || warnings[0].Element != faint {
+		t.Errorf("富文本应产生对比度告警: %+v", warnings)
+	}
+}
@@ -0,0 +1,68 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// ColorMode 输出颜色模式枚举
+type ColorMode string
+
+const (
+	ColorModeRGB           ColorMode = ""               // 彩色输出（默认）
+	ColorModeGray          ColorMode = "gray"           // 8位灰度
+	ColorModeMono          ColorMode = "mono"           // 1位黑白，Floyd-Steinberg抖动，适合图片
+	ColorModeMonoThreshold ColorMode = "mono_threshold" // 1位黑白，固定阈值，适合文字为主的小票
+)
+
+// 热敏打印机常用的打印宽度(像素，203dpi)
+const (
+	ThermalWidth58mm = 384 // 58mm纸宽
+	ThermalWidth80mm = 576 // 80mm纸宽
+)
+
+// monoPalette 1位黑白调色板，PNG编码时输出为1位深度
+var monoPalette = color.Palette{color.Black, color.White}
+
+// applyColorMode 按颜色模式转换图片
+func applyColorMode(img image.Image, mode ColorMode) image.Image {
+	switch mode {
+	case ColorModeGray:
+		return ToGray(img)
+	case ColorModeMono:
+		return ToMono(img, true)
+	case ColorModeMonoThreshold:
+		return ToMono(img, false)
+	default:
+		return img
+	}
+}
+
+// ToGray 将图片转换为8位灰度
+func ToGray(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	draw.Draw(gray, bounds, img, bounds.Min, draw.Src)
+	return gray
+}
+
+// ToMono 将图片转换为1位黑白图，dither为true时使用Floyd-Steinberg误差扩散
+func ToMono(img image.Image, dither bool) *image.Paletted {
+	bounds := img.Bounds()
+	mono := image.NewPaletted(bounds, monoPalette)
+	if dither {
+		draw.FloydSteinberg.Draw(mono, bounds, ToGray(img), bounds.Min)
+		return mono
+	}
+
+	gray := ToGray(img)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if gray.GrayAt(x, y).Y >= 128 {
+				mono.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return mono
+}
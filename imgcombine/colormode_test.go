@@ -0,0 +1,71 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// TestColorMode 测试灰度与1位黑白输出
+func TestColorMode(t *testing.T) {
+	newCombiner := func(mode ColorMode) *ImageCombiner {
+		combiner := NewImageCombiner(ThermalWidth58mm, 100)
+		combiner.OutputFormat = PNG
+		combiner.ColorMode = mode
+		rect := combiner.AddRectangleElement(0, 0, 192, 100)
+		rect.Color = color.RGBA{128, 128, 128, 255}
+		return combiner
+	}
+
+	data, err := newCombiner(ColorModeGray).ToBytes()
+	if err != nil {
+		t.Fatalf("生成灰度图失败: %v", err)
+	}
+	img, _ := png.Decode(bytes.NewReader(data))
+	if _, ok := img.(*image.Gray); !ok {
+		t.Errorf("灰度模式应输出灰度PNG，实际 %T", img)
+	}
+
+	data, err = newCombiner(ColorModeMono).ToBytes()
+	if err != nil {
+		t.Fatalf("生成黑白图失败: %v", err)
+	}
+	img, _ = png.Decode(bytes.NewReader(data))
+	paletted, ok := img.(*image.Paletted)
+	if !ok || len(paletted.Palette) != 2 {
+		t.Fatalf("黑白模式应输出双色PNG，实际 %T", img)
+	}
+	// 50%灰经抖动后黑白像素约各占一半，白色区域保持纯白
+	black := 0
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 192; x++ {
+			if paletted.ColorIndexAt(x, y) == 0 {
+				black++
+			}
+		}
+	}
+	if ratio := float64(black) / (192 * 100); ratio < 0.4 || ratio > 0.6 {
+		t.Errorf("抖动后黑色像素比例异常: %.2f", ratio)
+	}
+	if paletted.ColorIndexAt(300, 50) != 1 {
+		t.Error("白色区域应保持白色")
+	}
+
+	mono := ToMono(newImage(color.Gray{100}), false)
+	if mono.ColorIndexAt(0, 0) != 0 {
+		t.Error("阈值模式下深灰应为黑色")
+	}
+}
+
+// newImage 创建4x4纯色图片
+func newImage(c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
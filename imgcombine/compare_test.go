@@ -0,0 +1,54 @@
+package imgcombine
+
+import (
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCompareElement 测试对比滑块的左右分区、分隔线和扫动动画
+func TestCompareElement(t *testing.T) {
+	dir := t.TempDir()
+	paths := map[string]color.Color{
+		"before.png": color.RGBA{255, 0, 0, 255},
+		"after.png":  color.RGBA{0, 0, 255, 255},
+	}
+	for name, c := range paths {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		png.Encode(f, solidImage(50, 25, c))
+		f.Close()
+	}
+
+	combiner := NewImageCombiner(200, 100)
+	compare, err := combiner.AddCompareElement(filepath.Join(dir, "before.png"), filepath.Join(dir, "after.png"), 0, 0, 200, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compare.Split = 0.25
+	compare.HandleRadius = 0
+
+	img, _ := combiner.Combine()
+	if c := rgb(img.At(20, 80)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("分隔线左侧应为前图，实际 %v", c)
+	}
+	if c := rgb(img.At(150, 80)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("分隔线右侧应为后图，实际 %v", c)
+	}
+	if c := rgb(img.At(50, 80)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("分隔线应为白色，实际 %v", c)
+	}
+
+	sweep := compare.Sweep(4)
+	for frame, want := range []float64{0, 0.5, 1, 0.5} {
+		sweep(frame)
+		if math.Abs(compare.Split-want) > 1e-9 {
+			t.Errorf("第%d帧分隔线位置应为%v，实际 %v", frame, want, compare.Split)
+		}
+	}
+}
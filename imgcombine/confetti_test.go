@@ -0,0 +1,40 @@
+package imgcombine
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestConfettiSeed 测试相同种子渲染结果一致，且粒子只出现在指定区域
+func TestConfettiSeed(t *testing.T) {
+	render := func(seed int64) []byte {
+		combiner := NewImageCombiner(200, 200)
+		combiner.OutputFormat = PNG
+		confetti := combiner.AddConfettiElement(ParticleStar, 30, 0, 0, 100, 100)
+		confetti.Seed = seed
+		data, err := combiner.ToBytes()
+		if err != nil {
+			t.Fatalf("合成失败: %v", err)
+		}
+		return data
+	}
+
+	if !bytes.Equal(render(42), render(42)) {
+		t.Error("相同种子的渲染结果应一致")
+	}
+	if bytes.Equal(render(42), render(7)) {
+		t.Error("不同种子的渲染结果应不同")
+	}
+
+	combiner := NewImageCombiner(200, 200)
+	confetti := combiner.AddConfettiElement(ParticleConfetti, 50, 0, 0, 80, 80)
+	confetti.Seed = 1
+	img, _ := combiner.Combine()
+	for y := 100; y < 200; y++ {
+		for x := 100; x < 200; x++ {
+			if rgb(img.At(x, y)) != [3]uint32{255, 255, 255} {
+				t.Fatalf("粒子超出区域: (%d,%d)", x, y)
+			}
+		}
+	}
+}
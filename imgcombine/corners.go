@@ -0,0 +1,45 @@
+package imgcombine
+
+import (
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// CornerRadii 四个角各自的圆角半径，依次为左上、右上、右下、左下
+// 可用于顶部圆角、底部直角的卡片
+type CornerRadii [4]int
+
+// cornerRadii 返回四个角的圆角半径，设置了corners时使用之，否则四角均为roundCorner
+// 每个半径限制在短边的一半以内，四角均达到上限时为圆形或胶囊形
+func cornerRadii(roundCorner int, corners CornerRadii, width, height float64) [4]float64 {
+	if corners == (CornerRadii{}) {
+		corners = CornerRadii{roundCorner, roundCorner, roundCorner, roundCorner}
+	}
+	limit := math.Min(width, height) / 2
+	var r [4]float64
+	for i, c := range corners {
+		r[i] = math.Max(0, math.Min(float64(c), limit))
+	}
+	return r
+}
+
+// rounded 判断是否有任一角为圆角
+func rounded(r [4]float64) bool {
+	return r != [4]float64{}
+}
+
+// drawRoundedRect 按四个角各自的半径构建圆角矩形路径
+func drawRoundedRect(g *gg.Context, x, y, w, h float64, r [4]float64) {
+	g.NewSubPath()
+	g.MoveTo(x+r[0], y)
+	g.LineTo(x+w-r[1], y)
+	g.DrawArc(x+w-r[1], y+r[1], r[1], gg.Radians(270), gg.Radians(360))
+	g.LineTo(x+w, y+h-r[2])
+	g.DrawArc(x+w-r[2], y+h-r[2], r[2], gg.Radians(0), gg.Radians(90))
+	g.LineTo(x+r[3], y+h)
+	g.DrawArc(x+r[3], y+h-r[3], r[3], gg.Radians(90), gg.Radians(180))
+	g.LineTo(x, y+r[0])
+	g.DrawArc(x+r[0], y+r[0], r[0], gg.Radians(180), gg.Radians(270))
+	g.ClosePath()
+}
@@ -0,0 +1,100 @@
+package imgcombine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// CountdownFormat 倒计时数字格式枚举
+type CountdownFormat string
+
+const (
+	CountdownHMS         CountdownFormat = "hh:mm:ss"    // 时:分:秒，小时数可超过24（默认）
+	CountdownDHMS        CountdownFormat = "dd:hh:mm:ss" // 天:时:分:秒
+	CountdownMS          CountdownFormat = "mm:ss"       // 分:秒，分钟数可超过60
+	CountdownMSCentisecs CountdownFormat = "mm:ss.cc"    // 分:秒.百分秒，适合配合较高帧率
+)
+
+// Countdown 倒计时动画生成器，在合成器的背景和其他元素之上逐帧绘制剩余时间
+// 数字样式（字体、字号、颜色、对齐）通过Text设置，颜色可使用ThemeColor引用合成器主题
+type Countdown struct {
+	Combiner *ImageCombiner  // 绘制倒计时的合成器
+	Text     *TextElement    // 倒计时文本元素
+	Target   time.Time       // 目标时间
+	Start    time.Time       // 第一帧对应的时刻，为零值时使用当前时间
+	Format   CountdownFormat // 数字格式
+	FPS      int             // 每秒帧数
+	Duration time.Duration   // 动画时长
+	Loop     bool            // 是否循环播放，默认播放一次后停在最后一帧
+}
+
+// NewCountdown 在合成器画布中央添加倒计时文本，默认每秒1帧、时长60秒
+func NewCountdown(ic *ImageCombiner, target time.Time, fontSize float64) *Countdown {
+	text := ic.AddTextElement("", fontSize, ic.width/2, ic.height/2)
+	text.Anchor = AnchorCenter
+	return &Countdown{
+		Combiner: ic,
+		Text:     text,
+		Target:   target,
+		Format:   CountdownHMS,
+		FPS:      1,
+		Duration: time.Minute,
+	}
+}
+
+// Frames 返回动画的总帧数
+func (c *Countdown) Frames() int {
+	return max(1, int(c.Duration.Seconds()*float64(c.FPS)))
+}
+
+// At 返回t时刻显示的倒计时文本，目标时间已过时显示全零
+func (c *Countdown) At(t time.Time) string {
+	remaining := max(0, c.Target.Sub(t))
+	if c.Format != CountdownMSCentisecs {
+		// 截断到秒，与时钟一致：剩余1.5秒时显示1秒
+		remaining = remaining.Truncate(time.Second)
+	}
+	seconds := int64(remaining / time.Second)
+
+	switch c.Format {
+	case CountdownDHMS:
+		return fmt.Sprintf("%02d:%02d:%02d:%02d", seconds/86400, seconds/3600%24, seconds/60%60, seconds%60)
+	case CountdownMS:
+		return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+	case CountdownMSCentisecs:
+		return fmt.Sprintf("%02d:%02d.%02d", seconds/60, seconds%60, remaining%time.Second/(10*time.Millisecond))
+	default:
+		return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+}
+
+// WriteGIF 渲染倒计时并编码为GIF动画写入w，第i帧对应Start之后i/FPS秒
+func (c *Countdown) WriteGIF(w io.Writer) error {
+	if c.FPS < 1 {
+		return fmt.Errorf("fps must be positive")
+	}
+	start := c.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	return c.Combiner.WriteGIF(w, c.Frames(), c.FPS, c.Loop, func(frame int) {
+		// 按整数纳秒计算帧时刻，避免浮点累计误差
+		c.Text.Text = c.At(start.Add(time.Duration(frame) * time.Second / time.Duration(c.FPS)))
+	})
+}
+
+// SaveGIF 渲染倒计时并保存为GIF文件
+func (c *Countdown) SaveGIF(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := c.WriteGIF(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
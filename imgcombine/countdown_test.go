@@ -0,0 +1,69 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+// TestCountdownFormat 测试倒计时文本格式
+func TestCountdownFormat(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &Countdown{Target: now.Add(26*time.Hour + 3*time.Minute + 4*time.Second + 560*time.Millisecond)}
+
+	tests := []struct {
+		format CountdownFormat
+		want   string
+	}{
+		{CountdownHMS, "26:03:04"},
+		{CountdownDHMS, "01:02:03:04"},
+		{CountdownMS, "1563:04"},
+		{CountdownMSCentisecs, "1563:04.56"},
+	}
+	for _, tt := range tests {
+		c.Format = tt.format
+		if got := c.At(now); got != tt.want {
+			t.Errorf("%s 格式应为 %s，实际 %s", tt.format, tt.want, got)
+		}
+	}
+	if got := c.At(c.Target.Add(time.Hour)); got != "00:00.00" {
+		t.Errorf("目标时间已过应显示全零，实际 %s", got)
+	}
+}
+
+// TestCountdownGIF 测试倒计时按帧率生成GIF，相同的帧合并
+func TestCountdownGIF(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	combiner := NewImageCombiner(200, 80)
+	countdown := NewCountdown(combiner, start.Add(10*time.Second), 32)
+	countdown.Start = start
+	countdown.FPS = 2
+	countdown.Duration = 3 * time.Second
+
+	var buf bytes.Buffer
+	if err := countdown.WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10、9.5、9、8.5、8、7.5秒分别显示10、9、9、8、8、7
+	want := []int{50, 100, 100, 50}
+	if len(anim.Delay) != len(want) {
+		t.Fatalf("应合并为%d帧，实际 %d", len(want), len(anim.Delay))
+	}
+	for i, d := range want {
+		if anim.Delay[i] != d {
+			t.Errorf("第%d帧间隔应为%d，实际 %d", i, d, anim.Delay[i])
+		}
+	}
+	if anim.LoopCount != -1 {
+		t.Errorf("默认应只播放一次，实际 LoopCount=%d", anim.LoopCount)
+	}
+	if countdown.Text.Text != "00:00:07" {
+		t.Errorf("最后一帧应显示00:00:07，实际 %s", countdown.Text.Text)
+	}
+}
@@ -0,0 +1,66 @@
+package imgcombine
+
+import (
+	"image"
+	"image/draw"
+)
+
+// source 返回参与缩放和绘制的源图片，已修饰或抠图时使用处理结果，设置了裁剪区域时为该区域的子图，
+// 最后按FlipH和FlipV翻转
+func (ie *ImageElement) source() image.Image {
+	img := ie.crop(ie.uncropped())
+	if ie.FlipH || ie.FlipV {
+		img = flip(img, ie.FlipH, ie.FlipV)
+	}
+	return img
+}
+
+// uncropped 返回修饰、抠图之后，裁剪和翻转之前的图片
+func (ie *ImageElement) uncropped() image.Image {
+	if ie.RemoveBackground && ie.cutout != nil {
+		return ie.cutout
+	}
+	return ie.base()
+}
+
+// crop 返回裁剪区域的子图，未设置裁剪区域时返回原图
+// 裁剪坐标相对于未翻转图片的左上角，超出图片的部分会被忽略
+func (ie *ImageElement) crop(img image.Image) image.Image {
+	if ie.CropWidth <= 0 || ie.CropHeight <= 0 {
+		return img
+	}
+	bounds := img.Bounds()
+	rect := image.Rect(ie.CropX, ie.CropY, ie.CropX+ie.CropWidth, ie.CropY+ie.CropHeight).Add(bounds.Min).Intersect(bounds)
+	if rect.Empty() {
+		return img
+	}
+
+	// 复制为以原点为左上角的图片，后续缩放和透明度处理均假定图片从原点开始
+	cropped := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, rect.Min, draw.Src)
+	return cropped
+}
+
+// flip 返回水平和（或）垂直翻转后的图片
+func flip(img image.Image, horizontal, vertical bool) *image.RGBA {
+	bounds := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	w, h := bounds.Dx(), bounds.Dy()
+	flipped := image.NewRGBA(src.Rect)
+	for y := 0; y < h; y++ {
+		sy := y
+		if vertical {
+			sy = h - 1 - y
+		}
+		for x := 0; x < w; x++ {
+			sx := x
+			if horizontal {
+				sx = w - 1 - x
+			}
+			copy(flipped.Pix[flipped.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return flipped
+}
@@ -0,0 +1,178 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeKind 元素变更类型枚举
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"    // 新增元素
+	ChangeRemoved  ChangeKind = "removed"  // 删除元素
+	ChangeModified ChangeKind = "modified" // 属性变化
+	ChangeReplaced ChangeKind = "replaced" // 同一位置的元素类型变化
+)
+
+// PropertyChange 单个属性的变化，Path形如"Zones[1].Color"，新增或删除的属性对应值为nil
+type PropertyChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// ElementChange 单个元素的变化，元素按在合成器中的下标对应
+type ElementChange struct {
+	Index   int              `json:"index"`
+	Kind    ChangeKind       `json:"kind"`
+	Type    string           `json:"type"`              // 元素类型，替换时为新类型
+	OldType string           `json:"oldType,omitempty"` // 替换前的元素类型
+	Changes []PropertyChange `json:"changes,omitempty"` // 属性变化，仅ChangeModified时设置
+}
+
+// TemplateDiff 两个模板之间的结构化差异报告，可直接编码为JSON
+type TemplateDiff struct {
+	Settings []PropertyChange `json:"settings,omitempty"` // 画布尺寸、输出格式等合成器设置的变化
+	Elements []ElementChange  `json:"elements,omitempty"` // 元素的变化
+}
+
+// DiffTemplates 比较两个合成器，报告合成器设置与各元素属性的变化
+// 比较基于JSON序列化结果，元素类型须已注册
+func DiffTemplates(a, b *ImageCombiner) (*TemplateDiff, error) {
+	docA, err := templateDoc(a)
+	if err != nil {
+		return nil, err
+	}
+	docB, err := templateDoc(b)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &TemplateDiff{}
+	elementsA, _ := docA["elements"].([]any)
+	elementsB, _ := docB["elements"].([]any)
+	delete(docA, "elements")
+	delete(docB, "elements")
+	diffValues("", docA, docB, &diff.Settings)
+
+	for i := 0; i < max(len(elementsA), len(elementsB)); i++ {
+		switch {
+		case i >= len(elementsA):
+			diff.Elements = append(diff.Elements, ElementChange{Index: i, Kind: ChangeAdded, Type: elementType(elementsB[i])})
+		case i >= len(elementsB):
+			diff.Elements = append(diff.Elements, ElementChange{Index: i, Kind: ChangeRemoved, Type: elementType(elementsA[i])})
+		case elementType(elementsA[i]) != elementType(elementsB[i]):
+			diff.Elements = append(diff.Elements, ElementChange{Index: i, Kind: ChangeReplaced, Type: elementType(elementsB[i]), OldType: elementType(elementsA[i])})
+		default:
+			var changes []PropertyChange
+			diffValues("", elementsA[i].(map[string]any)["props"], elementsB[i].(map[string]any)["props"], &changes)
+			if len(changes) > 0 {
+				diff.Elements = append(diff.Elements, ElementChange{Index: i, Kind: ChangeModified, Type: elementType(elementsB[i]), Changes: changes})
+			}
+		}
+	}
+	return diff, nil
+}
+
+// Empty 判断两个模板是否没有差异
+func (d *TemplateDiff) Empty() bool {
+	return len(d.Settings) == 0 && len(d.Elements) == 0
+}
+
+// String 生成便于在代码评审中阅读的文本报告
+func (d *TemplateDiff) String() string {
+	var sb strings.Builder
+	for _, c := range d.Settings {
+		fmt.Fprintf(&sb, "~ %s: %s -> %s\n", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+	}
+	for _, e := range d.Elements {
+		switch e.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&sb, "+ [%d] %s\n", e.Index, e.Type)
+		case ChangeRemoved:
+			fmt.Fprintf(&sb, "- [%d] %s\n", e.Index, e.Type)
+		case ChangeReplaced:
+			fmt.Fprintf(&sb, "! [%d] %s -> %s\n", e.Index, e.OldType, e.Type)
+		default:
+			fmt.Fprintf(&sb, "~ [%d] %s\n", e.Index, e.Type)
+			for _, c := range e.Changes {
+				fmt.Fprintf(&sb, "    %s: %s -> %s\n", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// templateDoc 将合成器序列化为通用的JSON对象
+func templateDoc(ic *ImageCombiner) (map[string]any, error) {
+	data, err := ic.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// elementType 返回序列化元素的类型名
+func elementType(element any) string {
+	name, _ := element.(map[string]any)["type"].(string)
+	return name
+}
+
+// diffValues 递归比较两个JSON值，将变化的叶子属性追加到changes
+func diffValues(path string, a, b any, changes *[]PropertyChange) {
+	mapA, okA := a.(map[string]any)
+	mapB, okB := b.(map[string]any)
+	if okA && okB {
+		keys := make(map[string]bool)
+		for k := range mapA {
+			keys[k] = true
+		}
+		for k := range mapB {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			diffValues(child, mapA[k], mapB[k], changes)
+		}
+		return
+	}
+
+	sliceA, okA := a.([]any)
+	sliceB, okB := b.([]any)
+	if okA && okB && len(sliceA) == len(sliceB) {
+		for i := range sliceA {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), sliceA[i], sliceB[i], changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, PropertyChange{Path: path, Old: a, New: b})
+	}
+}
+
+// formatDiffValue 将属性值格式化为紧凑的JSON文本
+func formatDiffValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,56 @@
+package imgcombine
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// TestDiffTemplates 测试模板差异报告
+func TestDiffTemplates(t *testing.T) {
+	build := func() *ImageCombiner {
+		combiner := NewImageCombiner(400, 300)
+		combiner.AddRectangleElement(0, 0, 400, 300).Color = color.White
+		combiner.AddTextElement("标题", 30, 20, 50)
+		gauge := combiner.AddGaugeElement(60, 0, 100, 20, 100, 60)
+		gauge.Zones = []GaugeZone{{From: 0, To: 50, Color: color.RGBA{255, 0, 0, 255}}}
+		return combiner
+	}
+
+	a, b := build(), build()
+	diff, err := DiffTemplates(a, b)
+	if err != nil {
+		t.Fatalf("比较失败: %v", err)
+	}
+	if !diff.Empty() {
+		t.Fatalf("相同模板不应有差异:\n%s", diff)
+	}
+
+	b.OutputFormat = PNG
+	b.elements[1].(*TextElement).Text = "新标题"
+	b.elements[2].(*GaugeElement).Zones[0].Color = color.RGBA{0, 0, 255, 255}
+	b.AddDonutElement(50, 300, 100, 40)
+
+	diff, err = DiffTemplates(a, b)
+	if err != nil {
+		t.Fatalf("比较失败: %v", err)
+	}
+	if len(diff.Settings) != 1 || diff.Settings[0].Path != "outputFormat" || diff.Settings[0].New != "png" {
+		t.Errorf("合成器设置差异错误: %+v", diff.Settings)
+	}
+	if len(diff.Elements) != 3 {
+		t.Fatalf("应有3个元素变化，实际 %+v", diff.Elements)
+	}
+	if e := diff.Elements[0]; e.Index != 1 || e.Kind != ChangeModified || len(e.Changes) != 1 || e.Changes[0].Path != "Text" {
+		t.Errorf("文本变化错误: %+v", e)
+	}
+	if e := diff.Elements[1]; e.Kind != ChangeModified || e.Changes[0].Path != "Zones[0].Color" || e.Changes[0].New != "#0000ffff" {
+		t.Errorf("色区变化错误: %+v", e)
+	}
+	if e := diff.Elements[2]; e.Index != 3 || e.Kind != ChangeAdded || e.Type != "donut" {
+		t.Errorf("新增元素错误: %+v", e)
+	}
+	if report := diff.String(); !strings.Contains(report, `Text: "标题" -> "新标题"`) || !strings.Contains(report, "+ [3] donut") {
+		t.Errorf("文本报告不完整:\n%s", report)
+	}
+}
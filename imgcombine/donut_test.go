@@ -0,0 +1,27 @@
+package imgcombine
+
+import (
+	"testing"
+)
+
+// TestDonutElement 测试环形百分比进度范围
+func TestDonutElement(t *testing.T) {
+	combiner := NewImageCombiner(200, 200)
+
+	donut := combiner.AddDonutElement(25, 0, 0, 100)
+	donut.CenterText = "25%"
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+
+	// 25%从12点钟方向顺时针到3点钟方向，右上象限为进度色
+	if got := rgb(img.At(160, 30)); got != rgb(donut.Color) {
+		t.Errorf("右上象限应为进度色，实际 %v", got)
+	}
+	// 左下象限为底环色
+	if got := rgb(img.At(40, 170)); got != rgb(donut.TrackColor) {
+		t.Errorf("左下象限应为底环色，实际 %v", got)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !imgcombine_noembed
+
+package imgcombine
+
+import (
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+var (
+	embeddedFontOnce sync.Once
+	embeddedFont     *truetype.Font
+)
+
+// embeddedDefaultFont 返回编译进二进制的默认字体(Go Regular，BSD协议)
+// 在没有任何系统字体的容器中保证文本可以正常渲染，使用 -tags imgcombine_noembed 构建可去除
+func embeddedDefaultFont() *truetype.Font {
+	embeddedFontOnce.Do(func() {
+		embeddedFont, _ = truetype.Parse(goregular.TTF)
+	})
+	return embeddedFont
+}
@@ -0,0 +1,10 @@
+//go:build imgcombine_noembed
+
+package imgcombine
+
+import "github.com/golang/freetype/truetype"
+
+// embeddedDefaultFont 构建时去除了内置字体
+func embeddedDefaultFont() *truetype.Font {
+	return nil
+}
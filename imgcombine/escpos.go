@@ -0,0 +1,63 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image"
+	"io"
+)
+
+// escposBandHeight 每条GS v 0指令输出的最大行数，部分打印机缓冲区有限，分段发送更稳定
+const escposBandHeight = 256
+
+// ToESCPOS 将合成图片转换为ESC/POS光栅打印指令(GS v 0)，可直接发送给热敏打印机
+// ColorMode为mono_threshold时使用固定阈值，其余模式使用抖动转换为黑白；1位输出无法保留隐形水印
+func (ic *ImageCombiner) ToESCPOS() ([]byte, error) {
+	if err := ic.checkWatermarkOutput(false); err != nil {
+		return nil, err
+	}
+	img, err := ic.render()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeESCPOS(&buf, ToMono(img, ic.ColorMode != ColorModeMonoThreshold)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeESCPOS 将1位黑白图编码为ESC/POS光栅打印指令，先输出ESC @初始化打印机
+// 宽度不足8的倍数时右侧补白，高度超过escposBandHeight时分多条指令输出
+func EncodeESCPOS(w io.Writer, img *image.Paletted) error {
+	bounds := img.Bounds()
+	rowBytes := (bounds.Dx() + 7) / 8
+
+	if _, err := w.Write([]byte{0x1B, 0x40}); err != nil {
+		return err
+	}
+
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += escposBandHeight {
+		bottom := min(top+escposBandHeight, bounds.Max.Y)
+		height := bottom - top
+
+		band := make([]byte, 8, 8+rowBytes*height)
+		copy(band, []byte{0x1D, 0x76, 0x30, 0x00, byte(rowBytes), byte(rowBytes >> 8), byte(height), byte(height >> 8)})
+		for y := top; y < bottom; y++ {
+			row := make([]byte, rowBytes)
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				// 调色板下标0为黑色，对应打印点
+				if img.ColorIndexAt(x, y) == 0 {
+					i := x - bounds.Min.X
+					row[i/8] |= 0x80 >> (i % 8)
+				}
+			}
+			band = append(band, row...)
+		}
+
+		if _, err := w.Write(band); err != nil {
+			return err
+		}
+	}
+	return nil
+}
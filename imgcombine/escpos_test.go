@@ -0,0 +1,37 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+// TestESCPOS 测试光栅打印指令的头部、分段和点阵数据
+func TestESCPOS(t *testing.T) {
+	combiner := NewImageCombiner(10, 300)
+	combiner.ColorMode = ColorModeMonoThreshold
+	rect := combiner.AddRectangleElement(0, 0, 1, 300)
+	rect.Color = color.Black
+
+	data, err := combiner.ToESCPOS()
+	if err != nil {
+		t.Fatalf("生成打印指令失败: %v", err)
+	}
+
+	// 初始化指令 + 两段光栅指令(256行、44行)，每行2字节
+	if !bytes.HasPrefix(data, []byte{0x1B, 0x40, 0x1D, 0x76, 0x30, 0x00, 2, 0, 0, 1}) {
+		t.Fatalf("指令头部错误: % x", data[:10])
+	}
+	if want := 2 + 8 + 256*2 + 8 + 44*2; len(data) != want {
+		t.Fatalf("指令长度 %d，期望 %d", len(data), want)
+	}
+	second := data[2+8+256*2:]
+	if !bytes.Equal(second[:8], []byte{0x1D, 0x76, 0x30, 0x00, 2, 0, 44, 0}) {
+		t.Errorf("第二段指令头部错误: % x", second[:8])
+	}
+
+	// 每行仅最左侧一个黑点
+	if row := data[10:12]; row[0] != 0x80 || row[1] != 0 {
+		t.Errorf("点阵数据错误: % x", row)
+	}
+}
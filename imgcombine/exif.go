@@ -0,0 +1,123 @@
+package imgcombine
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// AutoOrient 解码JPEG时是否按EXIF方向标签旋转和翻转像素，使手机拍摄的照片正向显示
+var AutoOrient = true
+
+// exifOrientation 返回JPEG数据中EXIF方向标签的值(1-8)，缺失或无法解析时返回1
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xFF {
+			// 标记前的填充字节
+			i++
+			continue
+		}
+		if marker == 0x01 || marker >= 0xD0 && marker <= 0xD8 {
+			// 无长度字段的标记
+			i += 2
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			// 图像数据开始，EXIF只会出现在此之前
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation 在TIFF结构的第0个IFD中查找方向标签(0x0112)
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		// SHORT类型，值位于条目的值字段前两个字节
+		if v := int(order.Uint16(tiff[entry+8:])); order.Uint16(tiff[entry+2:]) == 3 && v >= 1 && v <= 8 {
+			return v
+		}
+		return 1
+	}
+	return 1
+}
+
+// orient 按EXIF方向值将图片变换为正向显示
+func orient(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return flip(img, true, false)
+	case 3:
+		return flip(img, true, true)
+	case 4:
+		return flip(img, false, true)
+	case 5, 6, 7, 8:
+		return transpose(img, orientation)
+	default:
+		return img
+	}
+}
+
+// transpose 处理需要交换宽高的方向值：5为沿主对角线翻转，6为顺时针旋转90度，
+// 7为沿副对角线翻转，8为逆时针旋转90度
+func transpose(img image.Image, orientation int) *image.RGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	out := image.NewRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < w; y++ {
+		for x := 0; x < h; x++ {
+			sx, sy := y, x
+			switch orientation {
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			out.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return out
+}
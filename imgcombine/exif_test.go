@@ -0,0 +1,76 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"testing"
+)
+
+// jpegWithOrientation 编码左红右蓝的JPEG并在SOI之后插入带方向标签的EXIF段
+func jpegWithOrientation(t *testing.T, orientation byte) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	draw.Draw(img, image.Rect(0, 0, 16, 16), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(16, 0, 32, 16), image.NewUniform(color.RGBA{0, 0, 255, 255}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00")
+	payload = append(payload, orientation, 0, 0, 0, 0, 0, 0)
+	segment := []byte{0xFF, 0xE1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	out = append(out, payload...)
+	return append(out, data[2:]...)
+}
+
+// TestExifOrientation 测试解码JPEG时按EXIF方向标签校正
+func TestExifOrientation(t *testing.T) {
+	red := func(c color.Color) bool {
+		r, _, b, _ := c.RGBA()
+		return r > b
+	}
+
+	tests := []struct {
+		orientation   byte
+		width, height int
+		redAt         image.Point
+	}{
+		{1, 32, 16, image.Pt(2, 8)},
+		{3, 32, 16, image.Pt(29, 8)},
+		{6, 16, 32, image.Pt(8, 2)},
+		{8, 16, 32, image.Pt(8, 29)},
+	}
+	for _, tt := range tests {
+		data := jpegWithOrientation(t, tt.orientation)
+		if got := exifOrientation(data); got != int(tt.orientation) {
+			t.Errorf("方向标签应为 %d，实际 %d", tt.orientation, got)
+		}
+		img, err := decodeImage(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("方向 %d 尺寸应为 %dx%d，实际 %v", tt.orientation, tt.width, tt.height, b)
+			continue
+		}
+		if !red(img.At(tt.redAt.X, tt.redAt.Y)) {
+			t.Errorf("方向 %d 时 %v 处应为红色", tt.orientation, tt.redAt)
+		}
+	}
+
+	AutoOrient = false
+	defer func() { AutoOrient = true }()
+	img, err := decodeImage(bytes.NewReader(jpegWithOrientation(t, 6)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 32 {
+		t.Errorf("关闭AutoOrient后不应旋转，实际 %v", b)
+	}
+}
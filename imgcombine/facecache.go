@@ -0,0 +1,59 @@
+package imgcombine
+
+import (
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// maxCachedFonts 可缓存的字体组合中最多包含的字体数（主字体加回退字体）
+const maxCachedFonts = 4
+
+// faceKey 字体缓存键
+type faceKey struct {
+	fonts [maxCachedFonts]*truetype.Font
+	size  float64
+}
+
+// faceCache 单次合成内的字体缓存
+// truetype的font.Face不能并发使用，缓存只在一次Combine内由同一goroutine访问
+type faceCache struct {
+	faces map[faceKey]font.Face
+}
+
+// face 返回字体组合在指定字号下的font.Face，缓存为nil时每次新建
+func (c *faceCache) face(fonts []*truetype.Font, fontSize float64) font.Face {
+	if c == nil || len(fonts) > maxCachedFonts {
+		return newFace(fonts, fontSize)
+	}
+
+	key := faceKey{size: fontSize}
+	copy(key.fonts[:], fonts)
+	if f, ok := c.faces[key]; ok {
+		return f
+	}
+	if c.faces == nil {
+		c.faces = make(map[faceKey]font.Face)
+	}
+	f := newFace(fonts, fontSize)
+	c.faces[key] = f
+	return f
+}
+
+// newFace 创建字体组合的font.Face，多个字体时按字符回退
+func newFace(fonts []*truetype.Font, fontSize float64) font.Face {
+	if len(fonts) == 1 {
+		return truetype.NewFace(fonts[0], &truetype.Options{Size: fontSize})
+	}
+	return newFallbackFace(fonts, fontSize)
+}
+
+// setFaceCache 为合成器中的文本元素注入字体缓存，传入nil时释放
+// 同时将元素关联到当前合成器，使字体设置以正在合成的合成器为准
+func (ic *ImageCombiner) setFaceCache(c *faceCache) {
+	for _, element := range ic.elements {
+		if te, ok := element.(*TextElement); ok {
+			te.faces = c
+			te.combiner = ic
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !js
+
+package imgcombine
+
+// defaultFontPaths 默认字体路径，按优先级排列，排在自定义字体之后尝试
+var defaultFontPaths = []string{
+	"Alibaba-PuHuiTi-Medium.ttf",
+	"/Library/Fonts/Arial.ttf",
+	"/System/Library/Fonts/PingFang.ttc",
+	"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+}
@@ -0,0 +1,7 @@
+//go:build js
+
+package imgcombine
+
+// defaultFontPaths 浏览器中无法访问系统字体，需通过FontRegistry.RegisterBytes注册字体，
+// 未注册时使用内置字体
+var defaultFontPaths []string
@@ -0,0 +1,297 @@
+package imgcombine
+
+import (
+	"fmt"
+	"hash/maphash"
+	"image"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// FontRegistry 字体注册表，按名称注册字体，字体只解析一次并缓存
+// 解析后的字体可在多个goroutine间共享，每次绘制按字号创建独立的font.Face
+type FontRegistry struct {
+	mu    sync.RWMutex
+	fonts map[string]*truetype.Font
+}
+
+// DefaultFontRegistry 默认字体注册表，合成器未指定Fonts时使用
+var DefaultFontRegistry = NewFontRegistry()
+
+// NewFontRegistry 创建空的字体注册表
+func NewFontRegistry() *FontRegistry {
+	return &FontRegistry{fonts: make(map[string]*truetype.Font)}
+}
+
+// RegisterBytes 以字体数据注册字体
+func (r *FontRegistry) RegisterBytes(name string, fontBytes []byte) error {
+	f, err := truetype.Parse(fontBytes)
+	if err != nil {
+		return fmt.Errorf("parse font %s: %v", name, err)
+	}
+
+	r.mu.Lock()
+	r.fonts[name] = f
+	r.mu.Unlock()
+	return nil
+}
+
+// RegisterFile 以字体文件注册字体
+func (r *FontRegistry) RegisterFile(name, path string) error {
+	fontBytes, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return r.RegisterBytes(name, fontBytes)
+}
+
+// RegisterFS 从文件系统注册字体，可直接传入embed.FS
+func (r *FontRegistry) RegisterFS(name string, fsys fs.FS, path string) error {
+	fontBytes, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return err
+	}
+	return r.RegisterBytes(name, fontBytes)
+}
+
+// Font 返回已注册的字体
+func (r *FontRegistry) Font(name string) (*truetype.Font, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.fonts[name]
+	return f, ok
+}
+
+// Face 按名称和字号创建字体
+func (r *FontRegistry) Face(name string, fontSize float64) (font.Face, error) {
+	f, ok := r.Font(name)
+	if !ok {
+		return nil, fmt.Errorf("font %q is not registered", name)
+	}
+	return truetype.NewFace(f, &truetype.Options{Size: fontSize}), nil
+}
+
+// fontFileCache 按路径缓存已解析的字体文件，避免每次绘制和测量都重新读取磁盘
+// 默认不限数量，长期运行且字体路径来自用户输入的服务可通过SetFontFileCacheLimit限制
+var fontFileCache = newLRUCache[string, *truetype.Font](0)
+
+// fontBytesCache 按内容哈希缓存已解析的FontBytes，同一份字体数据只解析一次，
+// 重复绘制得到同一个*truetype.Font，字体缓存(faceCache)才能命中
+var fontBytesCache = newLRUCache[fontBytesKey, *truetype.Font](0)
+
+// fontBytesKey 字体数据的哈希和长度
+type fontBytesKey struct {
+	hash uint64
+	size int
+}
+
+var fontBytesSeed = maphash.MakeSeed()
+
+// SetFontFileCacheLimit 设置缓存的已解析字体数量上限，按路径和按FontBytes缓存的字体分别计算，
+// 超出时淘汰最久未使用的字体，0表示不限制
+func SetFontFileCacheLimit(n int) {
+	fontFileCache.setLimit(n)
+	fontBytesCache.setLimit(n)
+}
+
+// loadFontFile 读取并解析字体文件，成功结果会被缓存
+func loadFontFile(path string) (*truetype.Font, error) {
+	if f, ok := fontFileCache.get(path); ok {
+		return f, nil
+	}
+
+	fontBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := truetype.Parse(fontBytes)
+	if err != nil {
+		return nil, err
+	}
+	fontFileCache.add(path, f, 1)
+	return f, nil
+}
+
+// parseFontBytes 解析字体数据，成功结果按内容缓存
+func parseFontBytes(fontBytes []byte) (*truetype.Font, error) {
+	key := fontBytesKey{maphash.Bytes(fontBytesSeed, fontBytes), len(fontBytes)}
+	if f, ok := fontBytesCache.get(key); ok {
+		return f, nil
+	}
+
+	f, err := truetype.Parse(fontBytes)
+	if err != nil {
+		return nil, err
+	}
+	fontBytesCache.add(key, f, 1)
+	return f, nil
+}
+
+// findFont 依次尝试自定义字体、默认字体路径和内置字体，全部失败时返回nil
+func findFont(fontPaths []string) *truetype.Font {
+	for _, path := range append(append([]string{}, fontPaths...), defaultFontPaths...) {
+		if f, err := loadFontFile(path); err == nil {
+			return f
+		}
+	}
+	return embeddedDefaultFont()
+}
+
+// loadFontFace 依次尝试加载自定义字体和默认字体，全部失败时保留gg默认字体
+func loadFontFace(g *gg.Context, fontPaths []string, fontSize float64) {
+	if f := findFont(fontPaths); f != nil {
+		g.SetFontFace(truetype.NewFace(f, &truetype.Options{Size: fontSize}))
+	}
+}
+
+// registry 返回文本元素使用的字体注册表：所属合成器的Fonts，未设置时为DefaultFontRegistry
+func (te *TextElement) registry() *FontRegistry {
+	if te.combiner != nil && te.combiner.Fonts != nil {
+		return te.combiner.Fonts
+	}
+	return DefaultFontRegistry
+}
+
+// fontPaths 返回文本元素的字体路径列表，元素未设置时使用所属合成器的FontPaths
+func (te *TextElement) fontPaths() []string {
+	if len(te.FontPaths) == 0 && te.combiner != nil {
+		return te.combiner.FontPaths
+	}
+	return te.FontPaths
+}
+
+// fallbackFonts 返回文本元素的回退字体，元素未设置时使用所属合成器的FallbackFonts
+func (te *TextElement) fallbackFonts() []string {
+	if len(te.FallbackFonts) == 0 && te.combiner != nil {
+		return te.combiner.FallbackFonts
+	}
+	return te.FallbackFonts
+}
+
+// resolveFont 解析文本元素的主字体，全部加载失败时返回nil
+// 优先级：FontBytes > FontFamily > FontPath > FontPaths > 默认字体 > 内置字体
+func (te *TextElement) resolveFont() *truetype.Font {
+	if len(te.FontBytes) > 0 {
+		if f, err := parseFontBytes(te.FontBytes); err == nil {
+			return f
+		}
+	}
+
+	if family := te.fontFamily(); family != "" {
+		if f, ok := te.registry().Font(family); ok {
+			return f
+		}
+	}
+
+	fontPaths := te.fontPaths()
+	if te.FontPath != "" {
+		fontPaths = append([]string{te.FontPath}, fontPaths...)
+	}
+	return findFont(fontPaths)
+}
+
+// resolveFallbackFonts 解析回退字体列表，每项可以是注册名或字体文件路径，无法加载的项被忽略
+func (te *TextElement) resolveFallbackFonts() []*truetype.Font {
+	var fonts []*truetype.Font
+	for _, name := range te.fallbackFonts() {
+		if f, ok := te.registry().Font(name); ok {
+			fonts = append(fonts, f)
+		} else if f, err := loadFontFile(name); err == nil {
+			fonts = append(fonts, f)
+		}
+	}
+	return fonts
+}
+
+// loadFont 加载文本元素的字体，返回字体缺少粗体/斜体变体时需要模拟的样式
+// 设置了回退字体时，每个字符使用第一个包含该字形的字体绘制
+func (te *TextElement) loadFont(g *gg.Context) synthStyle {
+	face, style := te.face()
+	if face != nil {
+		g.SetFontFace(face)
+	}
+	return style
+}
+
+// newFace 按指定字号创建文本元素的字体，family非空且已注册时替换主字体
+// 所有字体均加载失败时返回nil，调用方保留gg默认字体
+func (te *TextElement) newFace(fontSize float64, family string) font.Face {
+	fonts := te.resolveFallbackFonts()
+	primary, ok := te.registry().Font(family)
+	if family == "" || !ok {
+		primary = te.resolveFont()
+	}
+	if primary != nil {
+		fonts = append([]*truetype.Font{primary}, fonts...)
+	}
+
+	if len(fonts) == 0 {
+		return nil
+	}
+	return te.faces.face(fonts, fontSize)
+}
+
+// fallbackFace 按字符选择字体的组合字体，实现font.Face接口
+// 每个字符使用第一个包含该字形的字体，均不包含时使用第一个字体
+type fallbackFace struct {
+	fonts []*truetype.Font
+	faces []font.Face
+}
+
+// newFallbackFace 创建组合字体，fonts按优先级排列
+func newFallbackFace(fonts []*truetype.Font, fontSize float64) *fallbackFace {
+	faces := make([]font.Face, len(fonts))
+	for i, f := range fonts {
+		faces[i] = truetype.NewFace(f, &truetype.Options{Size: fontSize})
+	}
+	return &fallbackFace{fonts: fonts, faces: faces}
+}
+
+// pick 返回包含字符r的字体下标
+func (f *fallbackFace) pick(r rune) int {
+	for i, ft := range f.fonts {
+		if ft.Index(r) != 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+func (f *fallbackFace) Close() error {
+	for _, face := range f.faces {
+		face.Close()
+	}
+	return nil
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faces[f.pick(r)].Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faces[f.pick(r)].GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faces[f.pick(r)].GlyphAdvance(r)
+}
+
+// Kern 仅当两个字符来自同一字体时才应用字距调整
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	i := f.pick(r0)
+	if i != f.pick(r1) {
+		return 0
+	}
+	return f.faces[i].Kern(r0, r1)
+}
+
+// Metrics 使用主字体的度量
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
@@ -0,0 +1,232 @@
+package imgcombine
+
+import (
+	"image/color"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestFontRegistry 测试按名称注册字体并在文本元素中引用
+func TestFontRegistry(t *testing.T) {
+	fontBytes, err := os.ReadFile("../Alibaba-PuHuiTi-Medium.ttf")
+	if err != nil {
+		t.Fatalf("读取字体失败: %v", err)
+	}
+
+	registry := NewFontRegistry()
+	if err := registry.RegisterFS("puhui", fstest.MapFS{"puhui.ttf": {Data: fontBytes}}, "puhui.ttf"); err != nil {
+		t.Fatalf("注册字体失败: %v", err)
+	}
+	if err := registry.RegisterBytes("broken", []byte("not a font")); err == nil {
+		t.Error("无效字体数据应返回错误")
+	}
+	if _, err := registry.Face("missing", 20); err == nil {
+		t.Error("未注册的字体应返回错误")
+	}
+
+	combiner := NewImageCombiner(400, 100)
+	combiner.Fonts = registry
+	text := combiner.AddTextElement("中文", 40, 0, 50)
+	text.FontFamily = "puhui"
+
+	// 与直接指定字体文件的测量结果一致
+	byPath := &TextElement{Text: "中文", FontSize: 40, FontPath: "../Alibaba-PuHuiTi-Medium.ttf"}
+	if text.GetWidth() != byPath.GetWidth() {
+		t.Errorf("注册字体未生效: %.1f != %.1f", text.GetWidth(), byPath.GetWidth())
+	}
+
+	// 添加元素后再设置字体注册表，或通过AddElement添加的元素，同样使用合成器的字体设置
+	late := NewImageCombiner(400, 100)
+	lateText := late.AddTextElement("中文", 40, 0, 50)
+	lateText.FontFamily = "puhui"
+	added := &TextElement{Text: "中文", FontSize: 40, FontFamily: "puhui"}
+	late.AddElement(added)
+	late.Fonts = registry
+	if lateText.GetWidth() != byPath.GetWidth() || added.GetWidth() != byPath.GetWidth() {
+		t.Errorf("应使用合成器当前的字体注册表: %.1f %.1f != %.1f", lateText.GetWidth(), added.GetWidth(), byPath.GetWidth())
+	}
+	late.FontPaths = []string{"../Alibaba-PuHuiTi-Medium.ttf"}
+	byPaths := late.AddTextElement("中文", 40, 0, 50)
+	if byPaths.GetWidth() != byPath.GetWidth() || byPaths.FontPaths != nil {
+		t.Error("未设置FontPaths的元素应使用合成器的字体路径")
+	}
+
+	// 按路径加载的字体会被缓存
+	if _, ok := fontFileCache.get("../Alibaba-PuHuiTi-Medium.ttf"); !ok {
+		t.Error("字体文件应被缓存")
+	}
+}
+
+// TestFallbackFonts 测试缺少字形的字符从回退字体中查找
+func TestFallbackFonts(t *testing.T) {
+	const cjkFont = "../Alibaba-PuHuiTi-Medium.ttf"
+
+	// Go字体不包含中文字形
+	latinOnly := &TextElement{Text: "中", FontSize: 40, FontBytes: goregular.TTF}
+	withFallback := &TextElement{Text: "中", FontSize: 40, FontBytes: goregular.TTF, FallbackFonts: []string{"missing", cjkFont}}
+	cjk := &TextElement{Text: "中", FontSize: 40, FontPath: cjkFont}
+
+	if latinOnly.GetWidth() == cjk.GetWidth() {
+		t.Fatal("测试前提不成立：主字体不应包含中文字形")
+	}
+	if withFallback.GetWidth() != cjk.GetWidth() {
+		t.Errorf("中文应使用回退字体: %.1f != %.1f", withFallback.GetWidth(), cjk.GetWidth())
+	}
+
+	// 主字体包含的字符仍使用主字体
+	withFallback.Text = "A"
+	latinOnly.Text = "A"
+	if withFallback.GetWidth() != latinOnly.GetWidth() {
+		t.Errorf("英文应使用主字体: %.1f != %.1f", withFallback.GetWidth(), latinOnly.GetWidth())
+	}
+
+	// 合成器级别的回退字体会传递给文本元素
+	combiner := NewImageCombiner(100, 100)
+	text := combiner.AddTextElement("中", 40, 0, 50)
+	combiner.FallbackFonts = []string{cjkFont}
+	if len(text.fallbackFonts()) != 1 {
+		t.Error("文本元素应继承合成器的回退字体")
+	}
+}
+
+// TestEmbeddedDefaultFont 测试无可用字体文件时使用内置字体，文本宽度随字号缩放
+func TestEmbeddedDefaultFont(t *testing.T) {
+	if embeddedDefaultFont() == nil {
+		t.Skip("构建时去除了内置字体")
+	}
+
+	small := &TextElement{Text: "Hello", FontSize: 20, FontPaths: []string{"/not/exist.ttf"}}
+	large := &TextElement{Text: "Hello", FontSize: 40, FontPaths: []string{"/not/exist.ttf"}}
+	ratio := large.GetWidth() / small.GetWidth()
+	if ratio < 1.8 || ratio > 2.2 {
+		t.Errorf("文本宽度应随字号缩放，实际比例 %.2f", ratio)
+	}
+}
+
+// TestBoldItalic 测试粗体/斜体优先使用注册的变体，缺少变体时模拟
+func TestBoldItalic(t *testing.T) {
+	fontBytes, err := os.ReadFile("../Alibaba-PuHuiTi-Medium.ttf")
+	if err != nil {
+		t.Fatalf("读取字体失败: %v", err)
+	}
+	registry := NewFontRegistry()
+	registry.RegisterBytes("puhui", fontBytes)
+
+	render := func(bold, italic bool) (ink int, skew float64) {
+		combiner := NewImageCombiner(200, 80)
+		combiner.Fonts = registry
+		text := combiner.AddTextElement("丨丨丨", 50, 20, 60)
+		text.FontFamily = "puhui"
+		text.Bold = bold
+		text.Italic = italic
+		img, _ := combiner.Combine()
+
+		// 统计墨迹像素数，并比较上半部分与下半部分墨迹的平均横坐标
+		var top, bottom, topN, bottomN float64
+		for y := 0; y < 80; y++ {
+			for x := 0; x < 200; x++ {
+				if rgb(img.At(x, y))[0] > 128 {
+					continue
+				}
+				ink++
+				if y < 35 {
+					top, topN = top+float64(x), topN+1
+				} else {
+					bottom, bottomN = bottom+float64(x), bottomN+1
+				}
+			}
+		}
+		return ink, top/topN - bottom/bottomN
+	}
+
+	regularInk, regularSkew := render(false, false)
+	boldInk, _ := render(true, false)
+	_, italicSkew := render(false, true)
+	if boldInk <= regularInk {
+		t.Errorf("模拟粗体应加粗笔画: %d <= %d", boldInk, regularInk)
+	}
+	if italicSkew-regularSkew < 2 {
+		t.Errorf("模拟斜体应向右倾斜，偏移 %.1f", italicSkew-regularSkew)
+	}
+
+	// 注册粗体变体后使用变体字体，不再模拟
+	registry.RegisterBytes("puhui-Bold", goregular.TTF)
+	text := &TextElement{Text: "A", FontSize: 40, FontFamily: "puhui", Bold: true, Italic: true, combiner: &ImageCombiner{Fonts: registry}}
+	latin := &TextElement{Text: "A", FontSize: 40, FontBytes: goregular.TTF}
+	if text.GetWidth() != latin.GetWidth() {
+		t.Errorf("应使用注册的粗体变体: %.1f != %.1f", text.GetWidth(), latin.GetWidth())
+	}
+	if _, style := text.face(); style.bold || !style.italic {
+		t.Errorf("有粗体变体时只需模拟斜体: %+v", style)
+	}
+}
+
+// TestFaceCache 测试合成期间相同字体和字号共用font.Face，合成结束后释放
+func TestFaceCache(t *testing.T) {
+	f, err := loadFontFile("../Alibaba-PuHuiTi-Medium.ttf")
+	if err != nil {
+		t.Fatalf("读取字体失败: %v", err)
+	}
+	cache := &faceCache{}
+	if cache.face([]*truetype.Font{f}, 20) != cache.face([]*truetype.Font{f}, 20) {
+		t.Error("相同字体和字号应复用font.Face")
+	}
+	if cache.face([]*truetype.Font{f}, 20) == cache.face([]*truetype.Font{f}, 24) {
+		t.Error("不同字号应使用不同的font.Face")
+	}
+
+	combiner := NewImageCombiner(100, 100)
+	text := combiner.AddTextElement("缓存", 20, 0, 50)
+	combiner.Combine()
+	if text.faces != nil {
+		t.Error("合成结束后应释放字体缓存")
+	}
+
+	// FontBytes只解析一次，重复绘制命中字体缓存
+	byBytes := &TextElement{Text: "A", FontSize: 20, FontBytes: append([]byte(nil), goregular.TTF...), faces: cache}
+	if byBytes.resolveFont() != byBytes.resolveFont() {
+		t.Error("相同的字体数据应只解析一次")
+	}
+	if byBytes.newFace(20, "") != byBytes.newFace(20, "") {
+		t.Error("FontBytes字体应复用font.Face")
+	}
+}
+
+// TestTextTransparency 测试文本透明度作用于文字和删除线，粗体不叠加
+func TestTextTransparency(t *testing.T) {
+	render := func(transparency int) color.RGBA {
+		combiner := NewImageCombiner(120, 80)
+		text := combiner.AddTextElement("中", 60, 20, 60)
+		text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+		text.Bold = true
+		text.StrikeThrough = true
+		text.Transparency = transparency
+		img, _ := combiner.Combine()
+		// 取最暗的像素，即文字笔画最实的位置
+		darkest := color.RGBA{255, 255, 255, 255}
+		bounds := img.Bounds()
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA); c.R < darkest.R {
+					darkest = c
+				}
+			}
+		}
+		return darkest
+	}
+
+	if c := render(0); c.R != 0 {
+		t.Errorf("默认应不透明，最暗像素 %v", c)
+	}
+	if c := render(255); c.R != 255 {
+		t.Errorf("Transparency为255应完全透明，最暗像素 %v", c)
+	}
+	// 白底上黑色文字，半透明后最暗处约为128，粗体重复绘制不应叠加变深
+	if c := render(127); c.R < 120 || c.R > 135 {
+		t.Errorf("Transparency为127时最暗像素应约为127，实际 %v", c)
+	}
+}
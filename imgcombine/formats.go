@@ -0,0 +1,65 @@
+package imgcombine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+
+	// 注册WebP解码器
+	_ "golang.org/x/image/webp"
+)
+
+// isAVIF 判断数据是否为AVIF：ISO BMFF容器，第4-11字节为ftyp盒及主品牌avif（静态）或avis（序列）
+// 只在本包的decodeImage中识别，不通过image.RegisterFormat注册，避免宿主进程中其他image.Decode调用启动ffmpeg
+func isAVIF(data []byte) bool {
+	if len(data) < 12 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+	brand := string(data[8:12])
+	return brand == "avif" || brand == "avis"
+}
+
+// decodeAVIF 通过外部ffmpeg将AVIF转换为PNG后解码，Go标准库及golang.org/x/image均未提供AVIF解码器
+// AVIF的元数据可能位于文件末尾，ffmpeg需要可定位的输入，因此先写入临时文件
+func decodeAVIF(data []byte) (image.Image, error) {
+	f, err := os.CreateTemp("", "imgcombine-*.avif")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"-i", f.Name(),
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "png",
+		"-loglevel", "error",
+		"-",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ExternalToolTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, FFmpegPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("decode avif: timed out after %s", ExternalToolTimeout)
+		}
+		return nil, fmt.Errorf("decode avif: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	img, _, err := image.Decode(&stdout)
+	return img, err
+}
@@ -0,0 +1,94 @@
+package imgcombine
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestWebPDecode 测试加载WebP图片
+func TestWebPDecode(t *testing.T) {
+	// 1x1透明的无损WebP
+	data, _ := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	path := filepath.Join(t.TempDir(), "pixel.webp")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	img, err := LoadImage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 1 || b.Dy() != 1 {
+		t.Errorf("WebP尺寸错误: %v", b)
+	}
+}
+
+// TestAVIFDecode 以模拟的ffmpeg测试AVIF识别与解码
+func TestAVIFDecode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟的ffmpeg为shell脚本")
+	}
+	dir := t.TempDir()
+	var frame bytes.Buffer
+	png.Encode(&frame, solidImage(6, 4, color.RGBA{0, 255, 0, 255}))
+	framePath := filepath.Join(dir, "frame.png")
+	os.WriteFile(framePath, frame.Bytes(), 0644)
+
+	tool := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\ncat " + framePath + "\n"
+	if err := os.WriteFile(tool, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { FFmpegPath = path }(FFmpegPath)
+	FFmpegPath = tool
+
+	header := []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00avifmif1miaf")
+	if _, _, err := image.Decode(bytes.NewReader(header)); err == nil {
+		t.Error("AVIF不应注册到全局的image.Decode")
+	}
+	img, err := decodeImage(bytes.NewReader(header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 6 || b.Dy() != 4 {
+		t.Errorf("AVIF尺寸错误: %v", b)
+	}
+	if c := rgb(img.At(1, 1)); c != [3]uint32{0, 255, 0} {
+		t.Errorf("AVIF颜色错误: %v", c)
+	}
+}
+
+// TestAVIFDecodeFFmpeg 使用真实的ffmpeg编码并解码AVIF，未安装ffmpeg或不支持AV1编码时跳过
+func TestAVIFDecodeFFmpeg(t *testing.T) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		t.Skip("未安装ffmpeg")
+	}
+	defer func(path string) { FFmpegPath = path }(FFmpegPath)
+	FFmpegPath = ffmpeg
+
+	path := filepath.Join(t.TempDir(), "red.avif")
+	out, err := exec.Command(ffmpeg, "-loglevel", "error", "-f", "lavfi", "-i", "color=c=red:s=32x16",
+		"-frames:v", "1", "-c:v", "libaom-av1", "-still-picture", "1", path).CombinedOutput()
+	if err != nil {
+		t.Skipf("ffmpeg无法编码AVIF: %v: %s", err, out)
+	}
+
+	img, err := LoadImage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 32 || b.Dy() != 16 {
+		t.Errorf("AVIF尺寸错误: %v", b)
+	}
+	if r, g, b, _ := img.At(16, 8).RGBA(); r>>8 < 200 || g>>8 > 60 || b>>8 > 60 {
+		t.Errorf("AVIF颜色应接近红色，实际 %d %d %d", r>>8, g>>8, b>>8)
+	}
+}
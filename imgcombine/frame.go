@@ -0,0 +1,144 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+
+	"github.com/fogleman/gg"
+)
+
+// FrameStyle 图片边框样式枚举
+type FrameStyle string
+
+const (
+	FramePolaroid  FrameStyle = "polaroid" // 拍立得：白色宽边框，底部留出标题区域
+	FrameKeyline   FrameStyle = "keyline"  // 细线描边
+	FrameTornPaper FrameStyle = "torn"     // 撕纸边缘：以锯齿遮罩裁剪图片四边
+)
+
+// Frame 图片边框装饰，颜色可使用ThemeColor随主题变化
+type Frame struct {
+	Style        FrameStyle  // 边框样式
+	Color        color.Color // 边框颜色，拍立得为相纸颜色，细线为线条颜色
+	Width        int         // 边框宽度(像素)，拍立得为侧边宽度，撕纸为锯齿深度，0使用默认值
+	Caption      string      // 拍立得底部的标题
+	CaptionColor color.Color // 标题颜色
+	FontSize     float64     // 标题字号，0表示按底边高度自动计算
+	FontPaths    []string    // 标题字体路径列表
+}
+
+// NewFrame 按样式名称创建带默认配色的边框
+func NewFrame(style FrameStyle) *Frame {
+	frame := &Frame{Style: style}
+	switch style {
+	case FramePolaroid:
+		frame.Color = color.RGBA{250, 250, 248, 255}
+		frame.CaptionColor = color.RGBA{60, 60, 60, 255}
+	case FrameKeyline:
+		frame.Color = color.RGBA{0, 0, 0, 40}
+	}
+	return frame
+}
+
+// width 返回边框宽度，未设置时按样式和图片尺寸取默认值
+func (f *Frame) width(w, h int) int {
+	if f.Width > 0 {
+		return f.Width
+	}
+	switch f.Style {
+	case FramePolaroid:
+		return max(min(w, h)/20, 4)
+	case FrameTornPaper:
+		return max(min(w, h)/40, 3)
+	default:
+		return 1
+	}
+}
+
+// color 返回边框颜色，未设置时使用样式默认颜色
+func (f *Frame) color() color.Color {
+	if f.Color != nil {
+		return f.Color
+	}
+	return NewFrame(f.Style).Color
+}
+
+// prepare 在绘制图片前处理边框：撕纸样式裁剪图片，拍立得绘制相纸和标题
+// (x, y)为图片左上角，返回处理后的图片
+func (f *Frame) prepare(g *gg.Context, img image.Image, x, y int) image.Image {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	switch f.Style {
+	case FrameTornPaper:
+		return tornEdges(img, f.width(w, h))
+	case FramePolaroid:
+		b := f.width(w, h)
+		bottom := b * 4
+		g.Push()
+		defer g.Pop()
+
+		// 相纸与轻微投影
+		g.SetColor(color.RGBA{0, 0, 0, 30})
+		g.DrawRectangle(float64(x-b+2), float64(y-b+3), float64(w+2*b), float64(h+b+bottom))
+		g.Fill()
+		g.SetColor(f.color())
+		g.DrawRectangle(float64(x-b), float64(y-b), float64(w+2*b), float64(h+b+bottom))
+		g.Fill()
+
+		if f.Caption != "" {
+			fontSize := f.FontSize
+			if fontSize <= 0 {
+				fontSize = float64(bottom) * 0.4
+			}
+			loadFontFace(g, f.FontPaths, fontSize)
+			if f.CaptionColor != nil {
+				g.SetColor(f.CaptionColor)
+			} else {
+				g.SetColor(color.Black)
+			}
+			g.DrawStringAnchored(f.Caption, float64(x+w/2), float64(y+h+bottom/2), 0.5, 0.35)
+		}
+	}
+	return img
+}
+
+// finish 在绘制图片后处理边框：细线样式沿图片外沿描边
+func (f *Frame) finish(g *gg.Context, w, h, x, y int) {
+	if f.Style != FrameKeyline {
+		return
+	}
+	g.Push()
+	defer g.Pop()
+	lw := float64(f.width(w, h))
+	g.SetColor(f.color())
+	g.SetLineWidth(lw)
+	g.DrawRectangle(float64(x)-lw/2, float64(y)-lw/2, float64(w)+lw, float64(h)+lw)
+	g.Stroke()
+}
+
+// tornEdges 以不规则锯齿多边形裁剪图片四边，模拟撕纸效果
+// 锯齿由图片尺寸决定的种子生成，同一尺寸每次渲染结果一致
+func tornEdges(img image.Image, depth int) image.Image {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	rng := rand.New(rand.NewSource(int64(w)*7919 + int64(h)))
+	step := float64(max(depth*2, 4))
+	jitter := func() float64 { return rng.Float64() * float64(depth) }
+
+	mask := gg.NewContext(w, h)
+	for x := 0.0; x < float64(w); x += step {
+		mask.LineTo(x, jitter())
+	}
+	for y := 0.0; y < float64(h); y += step {
+		mask.LineTo(float64(w)-jitter(), y)
+	}
+	for x := float64(w); x > 0; x -= step {
+		mask.LineTo(x, float64(h)-jitter())
+	}
+	for y := float64(h); y > 0; y -= step {
+		mask.LineTo(jitter(), y)
+	}
+	mask.ClosePath()
+	mask.Clip()
+	mask.DrawImage(img, -img.Bounds().Min.X, -img.Bounds().Min.Y)
+	return mask.Image()
+}
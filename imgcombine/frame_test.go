@@ -0,0 +1,73 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// solidImage 创建纯色图片
+func solidImage(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// TestFrames 测试各边框样式
+func TestFrames(t *testing.T) {
+	blue := color.RGBA{0, 0, 255, 255}
+	render := func(frame *Frame) image.Image {
+		combiner := NewImageCombiner(200, 220)
+		combiner.AddRectangleElement(0, 0, 200, 220).Color = color.RGBA{128, 128, 128, 255}
+		combiner.AddElement(&ImageElement{image: solidImage(100, 100, blue), X: 50, Y: 40, ZoomMode: Origin, Alpha: 255, Frame: frame})
+		img, _ := combiner.Combine()
+		return img
+	}
+
+	polaroid := NewFrame(FramePolaroid)
+	polaroid.Caption = "Hi"
+	img := render(polaroid)
+	if c := rgb(img.At(52, 145)); c != [3]uint32{250, 250, 248} {
+		t.Errorf("拍立得底部应为相纸颜色，实际 %v", c)
+	}
+	if c := rgb(img.At(47, 40)); c != [3]uint32{250, 250, 248} {
+		t.Errorf("拍立得侧边应为相纸颜色，实际 %v", c)
+	}
+	dark := false
+	for x := 80; x < 120; x++ {
+		for y := 150; y < 170; y++ {
+			if c := rgb(img.At(x, y)); c[0] < 120 {
+				dark = true
+			}
+		}
+	}
+	if !dark {
+		t.Error("拍立得底部应绘制标题")
+	}
+
+	img = render(&Frame{Style: FrameKeyline, Color: color.Black, Width: 2})
+	if c := rgb(img.At(49, 80)); c != [3]uint32{0, 0, 0} {
+		t.Errorf("细线应描在图片外沿，实际 %v", c)
+	}
+	if c := rgb(img.At(51, 80)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("细线不应覆盖图片，实际 %v", c)
+	}
+
+	img = render(NewFrame(FrameTornPaper))
+	if c := rgb(img.At(100, 90)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("撕纸图片中央应保留，实际 %v", c)
+	}
+	torn := 0
+	for x := 50; x < 150; x++ {
+		if rgb(img.At(x, 40)) == [3]uint32{128, 128, 128} {
+			torn++
+		}
+	}
+	if torn == 0 || torn == 100 {
+		t.Errorf("撕纸上边缘应部分裁剪，裁剪像素 %d", torn)
+	}
+}
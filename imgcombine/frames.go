@@ -0,0 +1,181 @@
+package imgcombine
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+)
+
+// FrameUpdater 逐帧更新回调，frame从0开始
+// 在回调中修改元素属性（如进度条宽度、计数文本），随后该帧会被合成输出
+type FrameUpdater func(frame int)
+
+// SaveFrames 渲染frames帧并按序号保存为PNG序列
+// pattern为带序号占位符的文件路径，例如 "out/frame_%04d.png"
+func (ic *ImageCombiner) SaveFrames(pattern string, frames int, update FrameUpdater) error {
+	return ic.renderFrames(frames, update, func(frame int, img image.Image) error {
+		f, err := os.Create(fmt.Sprintf(pattern, frame))
+		if err != nil {
+			return err
+		}
+		return encodeFrame(f, frame, img)
+	})
+}
+
+// WriteFrames 渲染frames帧并将PNG依次写入w
+// 可直接作为ffmpeg的标准输入使用：ffmpeg -f image2pipe -i - out.mp4
+func (ic *ImageCombiner) WriteFrames(w io.Writer, frames int, update FrameUpdater) error {
+	return ic.renderFrames(frames, update, func(frame int, img image.Image) error {
+		return encodeFrame(nopWriteCloser{w}, frame, img)
+	})
+}
+
+// WriteGIF 以每秒fps帧渲染frames帧并编码为GIF动画写入w，loop为是否循环播放
+// 帧间隔按累计时间取整到GIF的1/100秒精度，连续相同的帧合并为一帧
+// 设置了隐形水印时帧颜色须不超过256种，否则抖动会破坏水印，返回ErrLossyWatermark
+func (ic *ImageCombiner) WriteGIF(w io.Writer, frames, fps int, loop bool, update FrameUpdater) error {
+	if fps < 1 {
+		return fmt.Errorf("fps must be positive")
+	}
+
+	anim := &gif.GIF{LoopCount: -1}
+	if loop {
+		anim.LoopCount = 0
+	}
+	var last image.Image
+	err := ic.renderFrames(frames, update, func(frame int, img image.Image) error {
+		delay := (frame+1)*100/fps - frame*100/fps
+		if last != nil && sameImage(last, img) {
+			anim.Delay[len(anim.Delay)-1] += delay
+			return nil
+		}
+		last = img
+		frameImg, exact := paletted(img)
+		if !exact && ic.InvisibleWatermark != "" {
+			return ErrLossyWatermark
+		}
+		anim.Image = append(anim.Image, frameImg)
+		anim.Delay = append(anim.Delay, delay)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// renderFrames 逐帧执行更新与合成，元素的入场动画按帧序号推进，合成结果交由emit输出
+// 设置了Boomerang时再倒序输出第frames-2帧到第1帧，首尾两帧不重复，共2*frames-2帧
+func (ic *ImageCombiner) renderFrames(frames int, update FrameUpdater, emit func(frame int, img image.Image) error) error {
+	if frames < 1 {
+		return fmt.Errorf("frames must be positive")
+	}
+	if err := ic.checkWatermarkOutput(true); err != nil {
+		return err
+	}
+
+	ic.animating = true
+	defer func() { ic.animating = false }()
+	ic.processCache().run++
+
+	var rendered []image.Image
+	for i := 0; i < frames; i++ {
+		ic.frame = i
+		if update != nil {
+			update(i)
+		}
+
+		img, err := ic.render()
+		if err != nil {
+			return err
+		}
+		if err := emit(i, img); err != nil {
+			return err
+		}
+		if ic.Boomerang {
+			rendered = append(rendered, img)
+		}
+	}
+
+	for i := frames - 2; i > 0 && ic.Boomerang; i-- {
+		if err := emit(2*frames-2-i, rendered[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeFrame 将一帧编码为PNG写入w并关闭
+func encodeFrame(w io.WriteCloser, frame int, img image.Image) error {
+	if err := png.Encode(w, img); err != nil {
+		w.Close()
+		return fmt.Errorf("encode frame %d: %v", frame, err)
+	}
+	return w.Close()
+}
+
+// paletted 将图片转换为GIF调色板图片，颜色不超过256种时使用原色，否则按Plan9调色板抖动
+// exact表示是否保留了原色
+func paletted(img image.Image) (out *image.Paletted, exact bool) {
+	bounds := img.Bounds()
+	seen := make(map[color.RGBA]bool)
+	pal := color.Palette{}
+	for y := bounds.Min.Y; y < bounds.Max.Y && pal != nil; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if seen[c] {
+				continue
+			}
+			if len(pal) == 256 {
+				pal = nil
+				break
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+
+	if pal == nil {
+		out = image.NewPaletted(bounds, palette.Plan9)
+		draw.FloydSteinberg.Draw(out, bounds, img, bounds.Min)
+		return out, false
+	}
+	out = image.NewPaletted(bounds, pal)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Src)
+	return out, true
+}
+
+// sameImage 判断两帧像素是否完全相同
+func sameImage(a, b image.Image) bool {
+	ra, ok1 := a.(*image.RGBA)
+	rb, ok2 := b.(*image.RGBA)
+	if ok1 && ok2 {
+		return ra.Rect == rb.Rect && bytes.Equal(ra.Pix, rb.Pix)
+	}
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if color.RGBAModel.Convert(a.At(x, y)) != color.RGBAModel.Convert(b.At(x, y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nopWriteCloser 为io.Writer补充空的Close方法
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,79 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// TestWriteFrames 测试进度条逐帧渲染为PNG序列
+func TestWriteFrames(t *testing.T) {
+	combiner := NewImageCombiner(100, 20)
+
+	// 进度条，每帧增加宽度
+	bar := combiner.AddRectangleElement(0, 0, 0, 20)
+	bar.Color = color.RGBA{255, 0, 0, 255}
+
+	var buf bytes.Buffer
+	err := combiner.WriteFrames(&buf, 4, func(frame int) {
+		bar.Width = (frame + 1) * 25
+	})
+	if err != nil {
+		t.Fatalf("渲染帧序列失败: %v", err)
+	}
+
+	// 依次解码每一帧，校验进度条长度
+	for i := 0; i < 4; i++ {
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("解码第%d帧失败: %v", i, err)
+		}
+		filled := (i+1)*25 - 1
+		if r, _, _, _ := img.At(filled, 10).RGBA(); r>>8 != 255 {
+			t.Errorf("第%d帧进度条未填充到 %d", i, filled)
+		}
+		if i < 3 {
+			if _, g, _, _ := img.At(filled+1, 10).RGBA(); g>>8 != 255 {
+				t.Errorf("第%d帧进度条超出 %d", i, filled)
+			}
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("帧数据有多余字节: %d", buf.Len())
+	}
+}
+
+// TestBoomerangFrames 测试往返导出在末尾追加倒放的帧
+func TestBoomerangFrames(t *testing.T) {
+	combiner := NewImageCombiner(100, 20)
+	combiner.Boomerang = true
+	bar := combiner.AddRectangleElement(0, 0, 0, 20)
+	bar.Color = color.RGBA{255, 0, 0, 255}
+
+	var buf bytes.Buffer
+	err := combiner.WriteFrames(&buf, 4, func(frame int) {
+		bar.Width = (frame + 1) * 25
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, width := range []int{25, 50, 75, 100, 75, 50} {
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("解码第%d帧失败: %v", i, err)
+		}
+		if r, _, _, _ := img.At(width-1, 10).RGBA(); r>>8 != 255 {
+			t.Errorf("第%d帧进度条应填充到 %d", i, width)
+		}
+		if width < 100 {
+			if _, g, _, _ := img.At(width, 10).RGBA(); g>>8 != 255 {
+				t.Errorf("第%d帧进度条超出 %d", i, width)
+			}
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("帧数据有多余字节: %d", buf.Len())
+	}
+}
@@ -0,0 +1,36 @@
+package imgcombine
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestGaugeElement 测试仪表盘色区与指针
+func TestGaugeElement(t *testing.T) {
+	combiner := NewImageCombiner(200, 200)
+
+	gauge := combiner.AddGaugeElement(75, 0, 100, 0, 0, 100)
+	gauge.Zones = []GaugeZone{
+		{From: 0, To: 50, Color: color.RGBA{255, 0, 0, 255}},
+		{From: 50, To: 100, Color: color.RGBA{0, 255, 0, 255}},
+	}
+	gauge.NeedleColor = color.RGBA{0, 0, 255, 255}
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+
+	// 左侧（160度附近）为低值红色区
+	if r, g, _, _ := img.At(15, 131).RGBA(); r>>8 != 255 || g>>8 != 0 {
+		t.Errorf("低值区应为红色，实际 r=%d g=%d", r>>8, g>>8)
+	}
+	// 右侧（20度附近）为高值绿色区
+	if r, g, _, _ := img.At(185, 131).RGBA(); r>>8 != 0 || g>>8 != 255 {
+		t.Errorf("高值区应为绿色，实际 r=%d g=%d", r>>8, g>>8)
+	}
+	// 75对应337.5度，指针指向右上方
+	if _, _, b, _ := img.At(140, 84).RGBA(); b>>8 != 255 {
+		t.Errorf("指针应指向右上方")
+	}
+}
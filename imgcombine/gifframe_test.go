@@ -0,0 +1,97 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestGIFFrame 测试按序号和时间点选择动图GIF的帧并叠加处置方式
+func TestGIFFrame(t *testing.T) {
+	pal := color.Palette{color.Transparent, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}, color.RGBA{0, 255, 0, 255}}
+	frame := func(rect image.Rectangle, index uint8) *image.Paletted {
+		img := image.NewPaletted(rect, pal)
+		for i := range img.Pix {
+			img.Pix[i] = index
+		}
+		return img
+	}
+	anim := &gif.GIF{
+		Image:    []*image.Paletted{frame(image.Rect(0, 0, 4, 4), 1), frame(image.Rect(0, 0, 2, 2), 2), frame(image.Rect(2, 2, 4, 4), 3)},
+		Delay:    []int{10, 20, 10},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone},
+		Config:   image.Config{ColorModel: pal, Width: 4, Height: 4},
+	}
+	path := filepath.Join(t.TempDir(), "anim.gif")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	img, err := LoadGIFFrame(path, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := rgb(img.At(0, 0)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("第1帧左上应为蓝色，实际 %v", c)
+	}
+	if c := rgb(img.At(3, 3)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("第1帧应保留第0帧的画面，实际 %v", c)
+	}
+
+	img, _ = LoadGIFFrame(path, 2, 0)
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Error("第1帧处置为背景后左上应透明")
+	}
+	if c := rgb(img.At(3, 3)); c != [3]uint32{0, 255, 0} {
+		t.Errorf("第2帧右下应为绿色，实际 %v", c)
+	}
+
+	if _, err := LoadGIFFrame(path, 3, 0); err == nil {
+		t.Error("帧序号越界应返回错误")
+	}
+
+	tests := []struct {
+		at   time.Duration
+		loop int
+		want int
+	}{
+		{150 * time.Millisecond, 0, 1},
+		{350 * time.Millisecond, 0, 2},
+		{450 * time.Millisecond, 0, 0},
+		{450 * time.Millisecond, -1, 2},
+		{450 * time.Millisecond, 1, 0},
+		{850 * time.Millisecond, 1, 2},
+	}
+	for _, tt := range tests {
+		anim.LoopCount = tt.loop
+		if got := gifFrameAt(anim, tt.at); got != tt.want {
+			t.Errorf("%v (LoopCount=%d) 应为第%d帧，实际 %d", tt.at, tt.loop, tt.want, got)
+		}
+	}
+
+	combiner := NewImageCombiner(4, 4)
+	if _, err := combiner.AddGIFFrameElement(path, 0, 150*time.Millisecond, 0, 0, Origin); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if c := rgb(restored.elements[0].(*ImageElement).image.At(0, 0)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("JSON恢复时应按时间点重新选择帧，实际 %v", c)
+	}
+}
@@ -0,0 +1,114 @@
+package imgcombine
+
+import (
+	"fmt"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// GlyphPaths 将单行文本按字体转换为矢量轮廓，每个字符一个PathElement（空白字符除外）
+// (x, y)为第一个字符的基线起点，字符间距包含字距调整；各路径的Origin为字符中心，
+// 可逐字修改X、Y、Rotate、Scale实现逐字动画，或修改路径点实现文字变形
+func GlyphPaths(f *truetype.Font, text string, fontSize, x, y float64) ([]*PathElement, error) {
+	if f == nil {
+		return nil, fmt.Errorf("glyph paths: no font")
+	}
+	scale := fixed.Int26_6(fontSize * 64)
+	var buf truetype.GlyphBuf
+	var paths []*PathElement
+	prev, hasPrev := truetype.Index(0), false
+	for _, r := range normalizeNewlines(text) {
+		if r == '\n' {
+			return nil, fmt.Errorf("glyph paths: text must be a single line")
+		}
+		index := f.Index(r)
+		if hasPrev {
+			x += float64(f.Kern(scale, prev, index)) / 64
+		}
+		prev, hasPrev = index, true
+
+		if err := buf.Load(f, scale, index, font.HintingNone); err != nil {
+			return nil, fmt.Errorf("glyph paths: load %q: %v", r, err)
+		}
+		if segments := glyphSegments(&buf, x, y); len(segments) > 0 {
+			path := &PathElement{Segments: segments}
+			minX, minY, maxX, maxY := path.Bounds()
+			path.Origin = gg.Point{X: (minX + maxX) / 2, Y: (minY + maxY) / 2}
+			paths = append(paths, path)
+		}
+		x += float64(f.HMetric(scale, index).AdvanceWidth) / 64
+	}
+	return paths, nil
+}
+
+// Paths 将文本元素转换为矢量轮廓，使用元素的字体、字号、位置和颜色，不支持换行和富文本片段
+func (te *TextElement) Paths() ([]*PathElement, error) {
+	paths, err := GlyphPaths(te.resolveFont(), te.Text, te.FontSize, float64(te.X), float64(te.Y))
+	if err != nil {
+		return nil, err
+	}
+	fill := te.textColor()
+	for _, path := range paths {
+		path.FillColor = fill
+	}
+	return paths, nil
+}
+
+// glyphSegments 将字形的二次贝塞尔轮廓转换为路径，字体坐标y轴向上，转换时翻转到基线(x, y)
+func glyphSegments(buf *truetype.GlyphBuf, x, y float64) []PathSegment {
+	var segments []PathSegment
+	pt := func(p truetype.Point) gg.Point {
+		return gg.Point{X: x + float64(p.X)/64, Y: y - float64(p.Y)/64}
+	}
+	mid := func(a, b gg.Point) gg.Point {
+		return gg.Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+	}
+
+	start := 0
+	for _, end := range buf.Ends {
+		ps := buf.Points[start:end]
+		start = end
+		if len(ps) == 0 {
+			continue
+		}
+
+		// 轮廓起点须为曲线上的点，首尾均为控制点时取两者中点
+		on := func(p truetype.Point) bool { return p.Flags&1 != 0 }
+		var first gg.Point
+		others := ps
+		switch last := ps[len(ps)-1]; {
+		case on(ps[0]):
+			first, others = pt(ps[0]), ps[1:]
+		case on(last):
+			first, others = pt(last), ps[:len(ps)-1]
+		default:
+			first = mid(pt(ps[0]), pt(last))
+		}
+		segments = append(segments, PathSegment{Op: PathMove, Points: []gg.Point{first}})
+
+		// 相邻两个控制点之间隐含一个曲线上的中点
+		q0, on0 := first, true
+		for _, p := range others {
+			q := pt(p)
+			switch {
+			case on(p) && on0:
+				segments = append(segments, PathSegment{Op: PathLine, Points: []gg.Point{q}})
+			case on(p):
+				segments = append(segments, PathSegment{Op: PathQuad, Points: []gg.Point{q0, q}})
+			case !on0:
+				segments = append(segments, PathSegment{Op: PathQuad, Points: []gg.Point{q0, mid(q0, q)}})
+			}
+			q0, on0 = q, on(p)
+		}
+		if on0 {
+			segments = append(segments, PathSegment{Op: PathLine, Points: []gg.Point{first}})
+		} else {
+			segments = append(segments, PathSegment{Op: PathQuad, Points: []gg.Point{q0, first}})
+		}
+		segments = append(segments, PathSegment{Op: PathClose})
+	}
+	return segments
+}
@@ -0,0 +1,57 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestGlyphPaths 测试文字轮廓与直接绘制的文字位置一致，且每个字符一个路径
+func TestGlyphPaths(t *testing.T) {
+	text := &TextElement{Text: "Ag 字", FontSize: 40, X: 10, Y: 60, Color: color.Black}
+	paths, err := text.Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("空格以外每个字符应有一个路径，实际 %d 个", len(paths))
+	}
+
+	drawn := NewImageCombiner(200, 100)
+	drawn.AddElement(text)
+	want, _ := drawn.Combine()
+
+	outlined := NewImageCombiner(200, 100)
+	for _, path := range paths {
+		outlined.AddElement(path)
+	}
+	got, _ := outlined.Combine()
+
+	rect := image.Rect(0, 0, 200, 100)
+	wantMin, wantMax, _ := inkBounds(want, rect)
+	gotMin, gotMax, ok := inkBounds(got, rect)
+	if !ok || abs(gotMin-wantMin) > 1 || abs(gotMax-wantMax) > 1 {
+		t.Errorf("轮廓水平范围应与文字一致，期望 %d-%d，实际 %d-%d", wantMin, wantMax, gotMin, gotMax)
+	}
+	// 字母g的下伸部分位于基线以下
+	if _, _, ok := inkBounds(got, image.Rect(0, 62, 200, 100)); !ok {
+		t.Error("轮廓应包含基线以下的下伸部分")
+	}
+
+	// 只描边的路径经JSON往返后保持不变
+	a := paths[0]
+	a.FillColor, a.StrokeColor, a.StrokeWidth = nil, color.Black, 1
+	a.Rotate = 15
+	data, err := json.Marshal(outlined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if r := restored.elements[0].(*PathElement); r.FillColor != nil || r.Rotate != 15 || len(r.Segments) != len(a.Segments) || r.Origin != a.Origin {
+		t.Errorf("路径未正确还原: %+v", r)
+	}
+}
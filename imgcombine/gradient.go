@@ -0,0 +1,150 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// GradientType 渐变类型枚举
+type GradientType string
+
+const (
+	GradientLinear GradientType = "linear" // 线性渐变
+	GradientRadial GradientType = "radial" // 径向渐变，从中心向外
+)
+
+// GradientStop 渐变色标
+type GradientStop struct {
+	Offset float64     // 位置(0-1)
+	Color  color.Color // 颜色
+}
+
+// Gradient 渐变填充，坐标相对于被填充内容的外接矩形
+type Gradient struct {
+	Type  GradientType   // 渐变类型，默认线性
+	Angle float64        // 线性渐变方向(度)，0为从左到右，90为从上到下
+	Stops []GradientStop // 色标，按Offset升序
+}
+
+// NewLinearGradient 创建线性渐变，颜色均匀分布
+func NewLinearGradient(angle float64, colors ...color.Color) *Gradient {
+	return &Gradient{Type: GradientLinear, Angle: angle, Stops: evenStops(colors)}
+}
+
+// NewRadialGradient 创建径向渐变，颜色由中心向外均匀分布
+func NewRadialGradient(colors ...color.Color) *Gradient {
+	return &Gradient{Type: GradientRadial, Stops: evenStops(colors)}
+}
+
+// NewFadeMask 创建淡出遮罩，沿angle方向从不透明过渡到完全透明
+// 如90度表示图片底部淡出，用作ImageElement.FadeMask
+func NewFadeMask(angle float64) *Gradient {
+	return NewLinearGradient(angle, color.NRGBA{0, 0, 0, 255}, color.NRGBA{0, 0, 0, 0})
+}
+
+// evenStops 将颜色均匀分布为色标
+func evenStops(colors []color.Color) []GradientStop {
+	stops := make([]GradientStop, len(colors))
+	for i, c := range colors {
+		if len(colors) > 1 {
+			stops[i].Offset = float64(i) / float64(len(colors)-1)
+		}
+		stops[i].Color = c
+	}
+	return stops
+}
+
+// pattern 创建覆盖矩形区域box的gg填充样式
+func (gr *Gradient) pattern(box image.Rectangle) gg.Pattern {
+	cx := float64(box.Min.X+box.Max.X) / 2
+	cy := float64(box.Min.Y+box.Max.Y) / 2
+	halfW := float64(box.Dx()) / 2
+	halfH := float64(box.Dy()) / 2
+
+	var p gg.Gradient
+	if gr.Type == GradientRadial {
+		p = gg.NewRadialGradient(cx, cy, 0, cx, cy, math.Hypot(halfW, halfH))
+	} else {
+		// 渐变轴穿过中心，长度恰好覆盖矩形在该方向上的投影
+		rad := gg.Radians(gr.Angle)
+		dx, dy := math.Cos(rad), math.Sin(rad)
+		extent := math.Abs(halfW*dx) + math.Abs(halfH*dy)
+		p = gg.NewLinearGradient(cx-dx*extent, cy-dy*extent, cx+dx*extent, cy+dy*extent)
+	}
+	for _, stop := range gr.Stops {
+		p.AddColorStop(stop.Offset, stop.Color)
+	}
+	return p
+}
+
+// drawGradient 以渐变填充绘制文本：先将文本绘制为遮罩，再在文本外接矩形内填充渐变
+func (te *TextElement) drawGradient(g *gg.Context, canvasWidth int) {
+	plain := *te
+	plain.Gradient = nil
+	plain.Color = color.White
+	plain.Spans = make([]TextSpan, len(te.Spans))
+	for i, span := range te.Spans {
+		span.Color = nil
+		plain.Spans[i] = span
+	}
+
+	scratch := getLayer(g.Width(), g.Height())
+	defer putLayer(scratch)
+	layer := gg.NewContextForRGBA(scratch)
+	plain.Draw(layer, canvasWidth)
+	mask := layer.AsMask()
+	box := alphaBounds(mask)
+	if box.Empty() {
+		return
+	}
+
+	g.Push()
+	defer g.Pop()
+	if err := g.SetMask(mask); err != nil {
+		return
+	}
+	g.SetFillStyle(te.Gradient.pattern(box))
+	g.DrawRectangle(float64(box.Min.X), float64(box.Min.Y), float64(box.Dx()), float64(box.Dy()))
+	g.Fill()
+}
+
+// alphaBounds 返回遮罩中非透明像素的外接矩形
+func alphaBounds(mask *image.Alpha) image.Rectangle {
+	var box image.Rectangle
+	bounds := mask.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if mask.AlphaAt(x, y).A > 0 {
+				box = box.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return box
+}
+
+// applyMask 以渐变各点颜色的透明度作为遮罩，按比例降低图片像素的透明度
+func (gr *Gradient) applyMask(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	p := gr.pattern(rgba.Bounds())
+	for y := 0; y < rgba.Rect.Dy(); y++ {
+		for x := 0; x < rgba.Rect.Dx(); x++ {
+			_, _, _, a := p.ColorAt(x, y).RGBA()
+			if a == 0xffff {
+				continue
+			}
+			// 预乘透明度的像素各通道同比缩放
+			i := rgba.PixOffset(x, y)
+			for c := i; c < i+4; c++ {
+				rgba.Pix[c] = uint8(uint32(rgba.Pix[c]) * a / 0xffff)
+			}
+		}
+	}
+	return rgba
+}
@@ -0,0 +1,76 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestGradientText 测试文本渐变填充从一端过渡到另一端
+func TestGradientText(t *testing.T) {
+	combiner := NewImageCombiner(400, 100)
+	text := combiner.AddTextElement("渐变标题渐变标题", 40, 20, 70)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.Gradient = NewLinearGradient(0, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+
+	// 找到最左和最右的实心像素，分别应偏红和偏蓝
+	left, right := [3]uint32{}, [3]uint32{}
+	leftX, rightX := img.Bounds().Max.X, -1
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 400; x++ {
+			c := rgb(img.At(x, y))
+			if c[0]+c[2] < 200 || c[1] > 60 {
+				continue // 背景或抗锯齿边缘
+			}
+			if x < leftX {
+				leftX, left = x, c
+			}
+			if x > rightX {
+				rightX, right = x, c
+			}
+		}
+	}
+	if rightX < 0 {
+		t.Fatal("未绘制文本")
+	}
+	if left[0] <= left[2] || right[2] <= right[0] {
+		t.Errorf("渐变方向错误: 左侧 %v，右侧 %v", left, right)
+	}
+
+	// 径向渐变中心与边缘颜色不同
+	box := alphaBounds(image.NewAlpha(image.Rect(0, 0, 10, 10)))
+	if !box.Empty() {
+		t.Error("透明遮罩的外接矩形应为空")
+	}
+	radial := NewRadialGradient(color.White, color.Black).pattern(image.Rect(0, 0, 100, 100))
+	if rgb(radial.ColorAt(50, 50))[0] < 200 || rgb(radial.ColorAt(0, 0))[0] > 50 {
+		t.Error("径向渐变应从中心的白色过渡到角落的黑色")
+	}
+}
+
+// TestImageFadeMask 测试图片渐变遮罩沿指定方向淡出到背景
+func TestImageFadeMask(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for i := 0; i < len(photo.Pix); i += 4 {
+		photo.Pix[i], photo.Pix[i+3] = 255, 255
+	}
+	combiner := NewImageCombiner(100, 100)
+	combiner.AddElement(&ImageElement{image: photo, ZoomMode: Origin, Alpha: 255, FadeMask: NewFadeMask(90)})
+
+	img, _ := combiner.Combine()
+	top, middle, bottom := rgb(img.At(50, 0)), rgb(img.At(50, 50)), rgb(img.At(50, 99))
+	if top[1] > 10 {
+		t.Errorf("顶部应保持原图红色，实际 %v", top)
+	}
+	if middle[1] < 100 || middle[1] > 155 {
+		t.Errorf("中部应半透明，实际 %v", middle)
+	}
+	if bottom[1] < 245 {
+		t.Errorf("底部应淡出为背景白色，实际 %v", bottom)
+	}
+}
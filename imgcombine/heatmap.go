@@ -0,0 +1,160 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// DefaultHeatmapColors 默认热力图色带：蓝-青-绿-黄-红
+var DefaultHeatmapColors = []color.Color{
+	color.RGBA{0, 0, 255, 255},
+	color.RGBA{0, 255, 255, 255},
+	color.RGBA{0, 255, 0, 255},
+	color.RGBA{255, 255, 0, 255},
+	color.RGBA{255, 0, 0, 255},
+}
+
+// HeatmapElement 热力图元素，将二维数值矩阵按色带渲染到指定区域
+// 常用于叠加在底图上展示点击/关注度分布
+type HeatmapElement struct {
+	X, Y        int           // 左上角坐标
+	Width       int           // 渲染宽度
+	Height      int           // 渲染高度
+	Data        [][]float64   // 数值矩阵，Data[行][列]
+	Min, Max    float64       // 数值映射范围，两者相等时按数据自动计算
+	Colors      []color.Color // 色带，由低到高，为空时使用DefaultHeatmapColors
+	Alpha       int           // 透明度(0-255)
+	Interpolate bool          // 是否在单元格之间进行双线性插值
+}
+
+// AddHeatmapElement 添加热力图元素
+func (ic *ImageCombiner) AddHeatmapElement(data [][]float64, x, y, width, height int) *HeatmapElement {
+	element := &HeatmapElement{
+		X:           x,
+		Y:           y,
+		Width:       width,
+		Height:      height,
+		Data:        data,
+		Alpha:       160,
+		Interpolate: true,
+	}
+
+	ic.AddElement(element)
+	return element
+}
+
+// valueRange 返回数值映射范围
+func (he *HeatmapElement) valueRange() (float64, float64) {
+	if he.Min != he.Max {
+		return he.Min, he.Max
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, row := range he.Data {
+		for _, v := range row {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	return lo, hi
+}
+
+// sample 在矩阵中按归一化坐标(0-1)取值
+func (he *HeatmapElement) sample(u, v float64) float64 {
+	rows := len(he.Data)
+	cols := len(he.Data[0])
+
+	if !he.Interpolate {
+		r := clampInt(int(v*float64(rows)), 0, rows-1)
+		c := clampInt(int(u*float64(cols)), 0, cols-1)
+		return he.at(r, c)
+	}
+
+	// 以单元格中心为采样点进行双线性插值
+	fy := v*float64(rows) - 0.5
+	fx := u*float64(cols) - 0.5
+	r0 := int(math.Floor(fy))
+	c0 := int(math.Floor(fx))
+	ty := fy - float64(r0)
+	tx := fx - float64(c0)
+
+	top := he.at(r0, c0)*(1-tx) + he.at(r0, c0+1)*tx
+	bottom := he.at(r0+1, c0)*(1-tx) + he.at(r0+1, c0+1)*tx
+	return top*(1-ty) + bottom*ty
+}
+
+// at 取矩阵值，越界坐标取最近的边缘值
+func (he *HeatmapElement) at(r, c int) float64 {
+	r = clampInt(r, 0, len(he.Data)-1)
+	row := he.Data[r]
+	if len(row) == 0 {
+		return 0
+	}
+	return row[clampInt(c, 0, len(row)-1)]
+}
+
+// Draw 实现CombineElement接口
+func (he *HeatmapElement) Draw(g *gg.Context, canvasWidth int) {
+	if he.Width <= 0 || he.Height <= 0 || len(he.Data) == 0 || len(he.Data[0]) == 0 {
+		return
+	}
+
+	colors := he.Colors
+	if len(colors) == 0 {
+		colors = DefaultHeatmapColors
+	}
+	lo, hi := he.valueRange()
+
+	layer := image.NewRGBA(image.Rect(0, 0, he.Width, he.Height))
+	for py := 0; py < he.Height; py++ {
+		v := (float64(py) + 0.5) / float64(he.Height)
+		for px := 0; px < he.Width; px++ {
+			u := (float64(px) + 0.5) / float64(he.Width)
+
+			t := 0.0
+			if hi > lo {
+				t = (he.sample(u, v) - lo) / (hi - lo)
+			}
+			layer.Set(px, py, colorRamp(colors, t))
+		}
+	}
+
+	g.DrawImage(CurrentAccelerator().ApplyAlpha(layer, he.Alpha), he.X, he.Y)
+}
+
+// colorRamp 在色带上按t(0-1)线性插值取色
+func colorRamp(colors []color.Color, t float64) color.Color {
+	if len(colors) == 1 {
+		return colors[0]
+	}
+	t = math.Max(0, math.Min(1, t))
+	pos := t * float64(len(colors)-1)
+	i := int(pos)
+	if i >= len(colors)-1 {
+		return colors[len(colors)-1]
+	}
+	return lerpColor(colors[i], colors[i+1], pos-float64(i))
+}
+
+// lerpColor 两个颜色之间线性插值
+func lerpColor(a, b color.Color, t float64) color.RGBA {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	mix := func(x, y uint32) uint8 {
+		return uint8((float64(x)*(1-t) + float64(y)*t) / 257)
+	}
+	return color.RGBA{mix(ar, br), mix(ag, bg), mix(ab, bb), mix(aa, ba)}
+}
+
+// clampInt 将v限制在[lo, hi]区间
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,35 @@
+package imgcombine
+
+import (
+	"testing"
+)
+
+// TestHeatmapElement 测试热力图色带映射
+func TestHeatmapElement(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+
+	heatmap := combiner.AddHeatmapElement([][]float64{
+		{0, 1},
+		{1, 2},
+	}, 0, 0, 100, 100)
+	heatmap.Alpha = 255
+	heatmap.Interpolate = false
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+
+	// 最小值为蓝色
+	if r, g, b, _ := img.At(10, 10).RGBA(); r>>8 != 0 || g>>8 != 0 || b>>8 != 255 {
+		t.Errorf("左上角应为蓝色，实际 %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+	// 最大值为红色
+	if r, g, b, _ := img.At(90, 90).RGBA(); r>>8 != 255 || g>>8 != 0 || b>>8 != 0 {
+		t.Errorf("右下角应为红色，实际 %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+	// 中间值为绿色
+	if r, g, b, _ := img.At(90, 10).RGBA(); r>>8 != 0 || g>>8 != 255 || b>>8 != 0 {
+		t.Errorf("右上角应为绿色，实际 %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
@@ -9,12 +9,13 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
+	"math/rand"
 	"net/http"
-	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/fogleman/gg"
-	"github.com/nfnt/resize"
 )
 
 // OutputFormat 输出图片格式枚举
@@ -30,10 +31,21 @@ const (
 type ZoomMode string
 
 const (
-	Origin      ZoomMode = "origin"      // 原始尺寸：不进行缩放，使用图片原始尺寸
-	Width       ZoomMode = "width"       // 按宽度缩放：保持原始宽高比，按指定宽度缩放
-	Height      ZoomMode = "height"      // 按高度缩放：保持原始宽高比，按指定高度缩放
+	Origin      ZoomMode = "origin"       // 原始尺寸：不进行缩放，使用图片原始尺寸
+	Width       ZoomMode = "width"        // 按宽度缩放：保持原始宽高比，按指定宽度缩放
+	Height      ZoomMode = "height"       // 按高度缩放：保持原始宽高比，按指定高度缩放
 	WidthHeight ZoomMode = "width_height" // 按宽高缩放：强制缩放到指定的宽度和高度，可能改变宽高比
+	NineSlice   ZoomMode = "nine_slice"   // 九宫格缩放：按Slice内边距缩放到指定的宽度和高度，四角不变形
+)
+
+// TextAlign 文本水平对齐方式枚举
+type TextAlign string
+
+const (
+	AlignLeft    TextAlign = "left"    // 左对齐
+	AlignCenter  TextAlign = "center"  // 居中对齐
+	AlignRight   TextAlign = "right"   // 右对齐
+	AlignJustify TextAlign = "justify" // 两端对齐：除最后一行外拉伸字间距（中文）或词间距（英文）填满MaxLineWidth
 )
 
 // CombineElement 组合元素接口
@@ -43,15 +55,61 @@ type CombineElement interface {
 
 // ImageElement 图片元素
 type ImageElement struct {
-	ImagePath   string      // 图片路径
-	X, Y        int         // 位置坐标
-	Width       int         // 宽度
-	Height      int         // 高度
-	Rotate      float64     // 旋转角度(度)
-	Alpha       int         // 透明度(0-255)
-	ZoomMode    ZoomMode    // 缩放模式
-	RoundCorner int         // 圆角半径
-	image       image.Image // 缓存的图片对象
+	ImagePath        string          // 图片路径
+	X, Y             int             // 位置坐标
+	Width            int             // 宽度
+	Height           int             // 高度
+	Rotate           float64         // 旋转角度(度)
+	Alpha            int             // 透明度(0-255)
+	ZoomMode         ZoomMode        // 缩放模式
+	Slice            Insets          // 九宫格内边距（源图像素），ZoomMode为NineSlice时使用，适用于气泡、按钮和边框
+	Repeat           RepeatMode      // 平铺方式，设置后在Width x Height区域内重复绘制图片，用于纹理背景和水印图案
+	TileWidth        int             // 平铺单元宽度，0时按TileHeight等比计算，均为0时使用原图尺寸
+	TileHeight       int             // 平铺单元高度
+	TileSpacing      int             // 平铺单元之间的间距
+	TileStagger      bool            // 奇数行错开半个单元
+	BlendMode        BlendMode       // 与画布的混合模式，默认正常覆盖
+	RoundCorner      int             // 圆角半径，四角相同时的简写
+	CornerRadii      CornerRadii     // 四个角各自的圆角半径，任一非零时代替RoundCorner
+	VideoFrame       bool            // ImagePath为视频，图片为FrameAt时间点的画面
+	FrameAt          time.Duration   // 视频帧时间点
+	PDFPage          int             // ImagePath为PDF，图片为该页（从1开始）的渲染结果，0表示非PDF
+	PDFDPI           float64         // PDF页面渲染分辨率，0使用150
+	GIFFrame         int             // ImagePath为动图GIF时选择的帧序号（从0开始）
+	GIFTime          time.Duration   // 动图GIF的播放时间点，大于0时代替GIFFrame选择帧
+	ResizeFilter     ResizeFilter    // 缩放插值算法，为空时使用DefaultResizeFilter
+	FallbackName     string          // 图片缺失或加载失败时以该名字生成首字母头像
+	FadeMask         *Gradient       // 透明度渐变遮罩，按色标颜色的透明度淡出图片，用于与背景自然融合
+	Reflection       *Reflection     // 倒影，为nil时不绘制
+	Frame            *Frame          // 边框装饰，为nil时不绘制
+	CropX, CropY     int             // 源图裁剪区域左上角，相对于图片左上角
+	CropWidth        int             // 源图裁剪区域宽度，与CropHeight均大于0时仅绘制该区域
+	CropHeight       int             // 源图裁剪区域高度
+	FlipH            bool            // 水平翻转（左右镜像），在裁剪之后进行
+	FlipV            bool            // 垂直翻转（上下镜像），在裁剪之后进行
+	Perspective      *Perspective    // 透视变换，为nil时按矩形绘制
+	Blur             float64         // 高斯模糊半径(像素)，缩放后模糊，用于模糊放大的背景图
+	Grayscale        bool            // 灰度
+	Sepia            bool            // 怀旧（棕褐色调）
+	Brightness       float64         // 亮度偏移(-1到1)，0不调整
+	Contrast         float64         // 对比度偏移(-1到1)，0不调整，-1为纯灰
+	Saturation       float64         // 饱和度偏移(-1到1)，0不调整，-1为灰度
+	AutoLevels       bool            // 自动色阶，按直方图拉伸各通道，改善灰暗或偏色的照片
+	LevelsClip       float64         // 自动色阶两端各忽略的像素百分比，0表示按最暗和最亮像素拉伸
+	TintColor        color.Color     // 着色颜色，为nil时不着色，其透明度按比例减弱着色
+	TintAmount       float64         // 着色强度(0-1)，0表示完全着色
+	BorderColor      color.Color     // 描边颜色，沿图片轮廓绘制，设置圆角时为圆角或圆形
+	BorderWidth      float64         // 描边宽度(像素)，描边位于图片区域内，0不描边
+	ChromaKey        *ChromaKey      // 颜色替换，在缩放前将接近目标颜色的像素替换为透明或指定颜色
+	RemoveBackground bool            // 合成前由合成器的BackgroundRemover去除背景，仅保留主体
+	Mask             *Mask           // 遮罩，以其透明度裁剪图片，用于心形、六边形等异形图片
+	Retouch          *RetouchOptions // 人像修饰（去红眼、磨皮），由合成器的Retoucher执行，为nil时不修饰
+	image            image.Image     // 缓存的图片对象
+	retouched        image.Image     // 人像修饰后的图片，只设置在合成时绘制的元素副本上，代替image
+	cutout           image.Image     // 去除背景后的图片，只设置在合成时绘制的元素副本上，代替image绘制
+	placeholder      bool            // image为首字母占位头像
+	svg              []byte          // SVG源数据，image为其按自身尺寸渲染的结果
+	raster           image.Image     // 按上次绘制尺寸渲染的SVG
 }
 
 // applyAlpha 为图片应用透明度
@@ -73,16 +131,36 @@ func applyAlpha(img image.Image, alpha int) image.Image {
 
 // TextElement 文本元素
 type TextElement struct {
-	Text          string      // 文本内容
-	FontSize      float64     // 字体大小
-	X, Y          int         // 文本位置坐标
-	Color         color.Color // 文本颜色
-	Rotate        float64     // 旋转角度(度)
-	MaxLineWidth  int         // 最大行宽，超出则自动换行(像素)
-	MaxLineCount  int         // 最大行数，超出部分将被截断
-	LineHeight    float64     // 行高，默认1.5倍字体大小
-	StrikeThrough bool        // 是否显示删除线
-	FontPaths     []string    // 自定义字体路径列表
+	Text           string         // 文本内容
+	FontSize       float64        // 字体大小
+	X, Y           int            // 文本位置坐标
+	Color          color.Color    // 文本颜色，为nil时使用主题的TextColor，未设置主题时为黑色
+	Rotate         float64        // 旋转角度(度)
+	MaxLineWidth   int            // 最大行宽，超出则自动换行(像素)
+	MaxLineCount   int            // 最大行数，超出部分将被截断
+	LineHeight     float64        // 行高，默认1.5倍字体大小
+	StrikeThrough  bool           // 是否显示删除线
+	FontPaths      []string       // 自定义字体路径列表，为空时使用合成器的FontPaths
+	Alignment      TextAlign      // 水平对齐方式，以MaxLineWidth为对齐宽度，默认左对齐
+	FontPath       string         // 当前元素专用字体文件(TTF/OTF)，优先于FontPaths
+	FontBytes      []byte         // 当前元素专用字体数据，优先于FontPath
+	FontFamily     string         // 字体注册表中的字体名称，优先于FontPath
+	FallbackFonts  []string       // 回退字体（注册名或文件路径），主字体缺少字形的字符依次从中查找，为空时使用合成器的FallbackFonts
+	Ellipsis       string         // 超出MaxLineCount被截断时追加到最后一行的后缀，如"…"，为空不追加
+	Spans          []TextSpan     // 富文本片段，设置后替代Text，各片段参与同一次换行
+	Vertical       bool           // 竖排：从上到下排列字符，列从右向左排列
+	MaxLineHeight  int            // 竖排时的最大列高，超出则自动换列(像素)
+	MaxColumnCount int            // 竖排时的最大列数，超出部分将被截断
+	Gradient       *Gradient      // 渐变填充，设置后替代Color及片段颜色
+	Bold           bool           // 粗体，优先使用注册的"FontFamily-Bold"字体，否则模拟
+	Italic         bool           // 斜体，优先使用注册的"FontFamily-Italic"字体，否则模拟
+	Transparency   int            // 透明度(0-255)，0为不透明，255为完全透明，作用于文字、渐变和删除线/下划线
+	Anchor         TextAnchor     // X/Y对应的文本块参考点，默认为首行基线左端
+	MaxHeight      int            // 最大高度(像素)，下一行超出时截断，与MaxLineCount同时生效时取较严格者
+	TabStops       []float64      // 制表位，相对行首的像素偏移(递增)，超出后每隔4倍字号一个；仅用于普通横排文本
+	BlendMode      BlendMode      // 与画布的混合模式，默认正常覆盖
+	combiner       *ImageCombiner // 所属合成器，绘制和测量时从中读取字体注册表、字体路径和回退字体
+	faces          *faceCache     // 合成期间注入的字体缓存
 }
 
 // RectangleElement 矩形元素，用于在图片上绘制矩形
@@ -92,18 +170,39 @@ type RectangleElement struct {
 	Width       int         // 矩形宽度
 	Height      int         // 矩形高度
 	Color       color.Color // 矩形填充颜色
-	RoundCorner int         // 矩形圆角半径，0表示直角矩形
+	RoundCorner int         // 矩形圆角半径，0表示直角矩形，四角相同时的简写
+	CornerRadii CornerRadii // 四个角各自的圆角半径，任一非零时代替RoundCorner
+	BlendMode   BlendMode   // 与画布的混合模式，默认正常覆盖
 }
 
 // ImageCombiner 图片合成器，用于管理和渲染多个图片元素
 // 支持添加图片、文本、矩形等元素，并将它们合成为单一图片
 type ImageCombiner struct {
-	width, height int          // 画布宽度和高度（像素）
-	context       *gg.Context  // 底层绘图上下文
-	elements      []CombineElement // 待合成的元素集合
-	OutputFormat  OutputFormat // 输出图片格式
-	quality       float64      // 输出图片质量（0.0-1.0），仅对JPG格式有效
-	FontPaths     []string     // 自定义字体路径列表
+	width, height        int               // 画布宽度和高度（像素）
+	context              *gg.Context       // 底层绘图上下文
+	elements             []CombineElement  // 待合成的元素集合
+	OutputFormat         OutputFormat      // 输出图片格式
+	quality              float64           // 输出图片质量（0.0-1.0），仅对JPG格式有效
+	FontPaths            []string          // 自定义字体路径列表
+	AccessibilitySidecar bool              // 保存图片时是否同时输出无障碍描述JSON（图片路径追加.json）
+	Moderator            Moderator         // 输出前的审核钩子，为nil时不审核
+	Fonts                *FontRegistry     // 字体注册表，为nil时使用DefaultFontRegistry
+	FallbackFonts        []string          // 文本回退字体（注册名或文件路径），用于混排中文、英文和表情符号
+	InvisibleWatermark   string            // 输出时嵌入的隐形水印内容，仅彩色PNG、GIF可保留，JPG、灰度、黑白及ESC/POS输出返回ErrLossyWatermark
+	ColorMode            ColorMode         // 输出颜色模式，灰度或1位黑白用于热敏打印
+	LayerCache           *LayerCache       // 图层缓存，为nil时不缓存，批量渲染时可在多个合成器间共享
+	Theme                *Theme            // 样式主题，提供新元素的默认值和ThemeColor引用的调色板
+	ColorScheme          ColorScheme       // 配色模式，深色时ThemeColor按Theme.Dark解析
+	BackgroundRemover    BackgroundRemover // 抠图钩子，处理设置了RemoveBackground的图片元素
+	Retoucher            Retoucher         // 人像修饰实现，为nil时使用DefaultRetoucher
+	Boomerang            bool              // 逐帧导出时在末尾追加倒放的帧序列，往返循环播放
+	seed                 int64             // 随机种子
+	seeded               bool              // 是否设置了随机种子
+	rng                  *rand.Rand        // 构建元素时使用的随机数生成器
+	animations           []*Animation      // 元素入场动画
+	processed            *processCache     // 图片元素的修饰、抠图结果
+	frame                int               // 逐帧渲染时的当前帧序号
+	animating            bool              // 是否处于逐帧渲染中
 }
 
 // NewImageCombiner 创建新的图片合成器
@@ -132,16 +231,40 @@ func (ic *ImageCombiner) SetQuality(quality int) error {
 
 // AddElement 添加元素到合成器
 func (ic *ImageCombiner) AddElement(element CombineElement) {
+	if te, ok := element.(*TextElement); ok {
+		te.combiner = ic
+	}
 	ic.elements = append(ic.elements, element)
 }
 
+// Grow 为即将添加的n个元素预分配空间，构建大量元素的模板时减少扩容
+func (ic *ImageCombiner) Grow(n int) {
+	ic.elements = slices.Grow(ic.elements, n)
+}
+
 // AddImageElement 添加图片元素
 func (ic *ImageCombiner) AddImageElement(imagePath string, x, y int, zoomMode ZoomMode) (*ImageElement, error) {
-	img, err := LoadImage(imagePath)
+	var svg []byte
+	var img image.Image
+	var err error
+	if isSVG(imagePath) {
+		svg, img, err = loadSVG(imagePath)
+	} else {
+		img, err = LoadImage(imagePath)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	element := ic.AddImageElementFromImage(img, x, y, zoomMode)
+	element.ImagePath = imagePath
+	element.svg = svg
+	return element, nil
+}
+
+// AddImageElementFromImage 添加已解码的图片元素，如程序生成的二维码
+// 元素没有ImagePath，序列化为JSON时以PNG编码内嵌图片数据
+func (ic *ImageCombiner) AddImageElementFromImage(img image.Image, x, y int, zoomMode ZoomMode) *ImageElement {
 	element := &ImageElement{
 		image:    img,
 		X:        x,
@@ -151,18 +274,30 @@ func (ic *ImageCombiner) AddImageElement(imagePath string, x, y int, zoomMode Zo
 	}
 
 	ic.AddElement(element)
-	return element, nil
+	return element
+}
+
+// AddImageElementFromBytes 解码内存中的图片数据并添加图片元素，支持的格式与LoadImage相同（SVG除外）
+func (ic *ImageCombiner) AddImageElementFromBytes(data []byte, x, y int, zoomMode ZoomMode) (*ImageElement, error) {
+	return ic.AddImageElementFromReader(bytes.NewReader(data), x, y, zoomMode)
+}
+
+// AddImageElementFromReader 从r读取并解码图片数据后添加图片元素，如通过gRPC接收的图片
+func (ic *ImageCombiner) AddImageElementFromReader(r io.Reader, x, y int, zoomMode ZoomMode) (*ImageElement, error) {
+	img, err := decodeImage(r)
+	if err != nil {
+		return nil, err
+	}
+	return ic.AddImageElementFromImage(img, x, y, zoomMode), nil
 }
 
 // AddTextElement 添加文本元素
 func (ic *ImageCombiner) AddTextElement(text string, fontSize float64, x, y int) *TextElement {
 	element := &TextElement{
-		Text:      text,
-		FontSize:  fontSize,
-		X:         x,
-		Y:         y,
-		Color:     color.Black,
-		FontPaths: ic.FontPaths,
+		Text:     text,
+		FontSize: fontSize,
+		X:        x,
+		Y:        y,
 	}
 
 	ic.AddElement(element)
@@ -185,11 +320,48 @@ func (ic *ImageCombiner) AddRectangleElement(x, y, width, height int) *Rectangle
 
 // Combine 执行图片合成
 func (ic *ImageCombiner) Combine() (image.Image, error) {
+	// 逐帧渲染时整个渲染过程为同一轮，不可比较钩子的处理结果在各帧间复用
+	if !ic.animating {
+		ic.processCache().run++
+	}
+	prepared := make(map[*ImageElement]*ImageElement)
+	if err := ic.retouchImages(prepared); err != nil {
+		return nil, err
+	}
+	if err := ic.removeBackgrounds(prepared); err != nil {
+		return nil, err
+	}
+
+	theme := ic.activeTheme()
 	ctx := gg.NewContext(ic.width, ic.height)
-	ctx.SetColor(color.White)
+	ctx.SetColor(theme.background())
 	ctx.Clear()
 
-	for _, element := range ic.elements {
+	// 同一次合成内相同字体和字号的文本共用font.Face，避免每个元素重复创建字形缓存
+	faces := &faceCache{}
+	defer ic.setFaceCache(nil)
+	ic.setFaceCache(faces)
+
+	for i, element := range ic.elements {
+		animation := ic.animation(element)
+		if re, ok := element.(RandomElement); ok {
+			element = re.WithRand(ic.elementRand(i))
+		}
+		if ie, ok := element.(*ImageElement); ok && prepared[ie] != nil {
+			element = prepared[ie]
+		}
+		element = theme.resolve(theme.withDefaults(element))
+		if animation != nil && ic.animating && animation.draw(ctx, element, ic.frame, ic.width) {
+			continue
+		}
+		if be, ok := element.(BlendedElement); ok && be.Blend() != BlendNormal {
+			drawBlended(ctx, element, be.Blend(), ic.width)
+			continue
+		}
+		if ce, ok := element.(CacheableElement); ok && ic.LayerCache != nil && ce.Cacheable() {
+			ic.LayerCache.draw(ctx, element, ic.width)
+			continue
+		}
 		element.Draw(ctx, ic.width)
 	}
 
@@ -198,24 +370,38 @@ func (ic *ImageCombiner) Combine() (image.Image, error) {
 
 // Save 将合成图片保存到文件
 func (ic *ImageCombiner) Save(filePath string) error {
-	img, err := ic.Combine()
+	if err := ic.checkWatermarkOutput(ic.OutputFormat != JPG); err != nil {
+		return err
+	}
+	img, err := ic.render()
 	if err != nil {
 		return err
 	}
 
 	switch ic.OutputFormat {
 	case JPG:
-		return gg.SaveJPG(filePath, img, int(ic.quality*100))
+		err = gg.SaveJPG(filePath, img, int(ic.quality*100))
 	case PNG:
-		return gg.SavePNG(filePath, img)
+		err = gg.SavePNG(filePath, img)
 	default:
 		return fmt.Errorf("unsupported output format: %s", ic.OutputFormat)
 	}
+	if err != nil {
+		return err
+	}
+
+	if ic.AccessibilitySidecar {
+		return ic.SaveAccessibility(filePath + ".json")
+	}
+	return nil
 }
 
 // ToBytes 将合成图片编码为[]byte返回
 func (ic *ImageCombiner) ToBytes() ([]byte, error) {
-	img, err := ic.Combine()
+	if err := ic.checkWatermarkOutput(ic.OutputFormat != JPG); err != nil {
+		return nil, err
+	}
+	img, err := ic.render()
 	if err != nil {
 		return nil, err
 	}
@@ -237,8 +423,12 @@ func (ic *ImageCombiner) ToBytes() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// loadImage 从路径加载图片
+// LoadImage 从路径加载图片，SVG按自身尺寸渲染
 func LoadImage(path string) (image.Image, error) {
+	if isSVG(path) {
+		_, img, err := loadSVG(path)
+		return img, err
+	}
 	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
 		resp, err := http.Get(path)
 		if err != nil {
@@ -248,7 +438,7 @@ func LoadImage(path string) (image.Image, error) {
 		return decodeImage(resp.Body)
 	}
 
-	file, err := os.Open(path)
+	file, err := openLocal(path)
 	if err != nil {
 		return nil, err
 	}
@@ -257,21 +447,43 @@ func LoadImage(path string) (image.Image, error) {
 	return decodeImage(file)
 }
 
-// decodeImage 解码图片数据
+// decodeImage 解码图片数据，AVIF交由ffmpeg解码，启用AutoOrient时按EXIF方向标签校正JPEG
 func decodeImage(r io.Reader) (image.Image, error) {
-	img, _, err := image.Decode(r)
-	return img, err
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if isAVIF(data) {
+		return decodeAVIF(data)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if AutoOrient && format == "jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+	return img, nil
 }
 
 // Draw 实现CombineElement接口
 func (ie *ImageElement) Draw(g *gg.Context, canvasWidth int) {
+	if ie.image == nil {
+		return
+	}
+
 	// 实现图片绘制逻辑
 	g.Push()
 	defer g.Pop()
 
-	// 获取原始图片尺寸
-	origWidth := ie.image.Bounds().Dx()
-	origHeight := ie.image.Bounds().Dy()
+	// 获取原始图片尺寸，设置了裁剪区域时为裁剪后的尺寸
+	source := ie.source()
+	if ie.ChromaKey != nil {
+		source = ie.ChromaKey.apply(source)
+	}
+	origWidth := source.Bounds().Dx()
+	origHeight := source.Bounds().Dy()
 
 	// 根据ZoomMode计算缩放后的尺寸
 	width, height := ie.Width, ie.Height
@@ -292,245 +504,299 @@ func (ie *ImageElement) Draw(g *gg.Context, canvasWidth int) {
 			ratio := float64(origWidth) / float64(origHeight)
 			width = int(float64(height) * ratio)
 		}
-	case WidthHeight:
+	case WidthHeight, NineSlice:
 		// 指定高度和宽度，强制缩放
 		width = ie.Width
 		height = ie.Height
 	}
 
-	// 创建缩放后的图片
-	// 直接使用resize进行缩放，获取image.Image对象
-	scaledImg := resize.Resize(uint(width), uint(height), ie.image, resize.Lanczos3)
+	// 创建缩放后的图片，由当前加速后端执行
+	var scaledImg image.Image
+	switch {
+	case ie.Repeat != NoRepeat:
+		// 平铺时Width和Height为平铺区域，单元尺寸由TileWidth和TileHeight决定
+		width, height = ie.Width, ie.Height
+		scaledImg = ie.tile(source, width, height)
+	case ie.ZoomMode == NineSlice:
+		scaledImg = nineSlice(source, ie.Slice, width, height, ie.ResizeFilter)
+	case ie.vector():
+		// SVG按目标尺寸重新渲染，保持任意缩放下的清晰度
+		scaledImg = ie.rasterize(width, height)
+	default:
+		scaledImg = resizeWith(source, width, height, ie.ResizeFilter)
+	}
+	if ie.Blur > 0 {
+		scaledImg = CurrentAccelerator().Blur(scaledImg, ie.Blur)
+	}
+	if ie.AutoLevels {
+		scaledImg = applyAutoLevels(scaledImg, ie.LevelsClip)
+	}
+	if ie.adjusted() {
+		scaledImg = ie.applyAdjustments(scaledImg)
+	}
+	if ie.TintColor != nil {
+		amount := ie.TintAmount
+		if amount == 0 {
+			amount = 1
+		}
+		scaledImg = applyTint(scaledImg, ie.TintColor, amount)
+	}
 
 	// 处理圆角
-	if ie.RoundCorner > 0 {
+	if radii := ie.cornerRadii(width, height); rounded(radii) {
 		// 创建圆角蒙版
 		mask := gg.NewContext(width, height)
 
-		// 计算最大可能的圆角半径（用于圆形效果）
-		maxRadius := float64(width)
-		if height < width {
-			maxRadius = float64(height)
-		}
-		maxRadius /= 2
-
-		// 如果设置的圆角半径大于等于最大半径，则使用最大半径形成圆形
-		cornerRadius := float64(ie.RoundCorner)
-		if cornerRadius >= maxRadius {
-			cornerRadius = maxRadius
-		}
-
-		mask.DrawRoundedRectangle(0, 0, float64(width), float64(height), cornerRadius)
+		// 圆角半径大于等于短边一半时形成圆形
+		drawRoundedRect(mask, 0, 0, float64(width), float64(height), radii)
 		mask.Clip()
 		mask.DrawImage(scaledImg, 0, 0)
 		scaledImg = mask.Image()
 	}
 
+	// 应用形状或图片遮罩
+	if ie.Mask != nil {
+		scaledImg = ie.Mask.apply(scaledImg)
+	}
+
+	// 应用渐变遮罩
+	if ie.FadeMask != nil {
+		scaledImg = ie.FadeMask.applyMask(scaledImg)
+	}
+
 	// 应用透明度到图片
-	modifiedImage := applyAlpha(scaledImg, ie.Alpha)
+	modifiedImage := CurrentAccelerator().ApplyAlpha(scaledImg, ie.Alpha)
+
+	if ie.Perspective != nil {
+		ie.Perspective.draw(g, modifiedImage, ie.X, ie.Y)
+		return
+	}
 
-	// 处理旋转
+	// 处理旋转：旋转时以图片中心为原点绘制，倒影随图片一起旋转
+	x, y := ie.X, ie.Y
 	if ie.Rotate != 0 {
 		g.Translate(float64(ie.X+width/2), float64(ie.Y+height/2))
 		g.Rotate(gg.Radians(ie.Rotate))
-		g.DrawImage(modifiedImage, -width/2, -height/2)
-	} else {
-		g.DrawImage(modifiedImage, ie.X, ie.Y)
+		x, y = -width/2, -height/2
+	}
+	if ie.Frame != nil {
+		modifiedImage = ie.Frame.prepare(g, modifiedImage, x, y)
+	}
+	g.DrawImage(modifiedImage, x, y)
+	ie.drawBorder(g, width, height, x, y)
+	if ie.Frame != nil {
+		ie.Frame.finish(g, width, height, x, y)
+	}
+
+	if ie.Reflection != nil {
+		if reflection := ie.Reflection.image(modifiedImage); reflection != nil {
+			g.DrawImage(reflection, x, y+height+ie.Reflection.Gap)
+		}
 	}
 }
 
 // GetWidth 计算文本元素的宽度，考虑自动换行后的最长行宽度
 func (te *TextElement) GetWidth() float64 {
-	// 创建足够大的上下文以确保文本测量准确性
-	g := gg.NewContext(10000, 100)
+	return te.Measure().Width
+}
 
-	// 加载字体，与Draw方法保持一致
+// wrapLines 按当前字体将文本拆分为行，Draw与GetWidth共用同一套换行逻辑
+// 换行符始终强制换行，设置了最大行宽时各段再自动换行，最后应用最大行数限制
+func (te *TextElement) wrapLines(g *gg.Context) []string {
+	lines, _ := te.wrapParagraphs(g)
+	return lines
+}
 
-	// 默认字体路径优先级定义
-	var defaultFontPaths = []string{
-		"Alibaba-PuHuiTi-Medium.ttf",
-		"/Library/Fonts/Arial.ttf",
-		"/System/Library/Fonts/PingFang.ttc",
+// wrapParagraphs 按换行符分段后逐段自动换行，ends标记各行是否为段落的最后一行
+func (te *TextElement) wrapParagraphs(g *gg.Context) (lines []string, ends []bool) {
+	for _, paragraph := range strings.Split(normalizeNewlines(te.Text), "\n") {
+		wrapped := te.wrapParagraph(g, paragraph)
+		for i := range wrapped {
+			ends = append(ends, i == len(wrapped)-1)
+		}
+		lines = append(lines, wrapped...)
 	}
 
-	// 优化字体加载逻辑：使用预定义的字体路径
-	fontPaths := append(te.FontPaths, defaultFontPaths...)
-
-	var loadErr error
-	for _, path := range fontPaths {
-		if loadErr = g.LoadFontFace(path, te.FontSize); loadErr == nil {
-			break // 成功加载字体，退出循环
+	// 应用最大行数和最大高度限制：截断超出部分
+	if limit := te.maxLines(te.plainExtents()); limit > 0 && len(lines) > limit {
+		lines, ends = lines[:limit], ends[:limit]
+		ends[len(ends)-1] = true
+		if te.Ellipsis != "" {
+			lines[len(lines)-1] = te.appendEllipsis(g, lines[len(lines)-1])
 		}
 	}
+	return lines, ends
+}
 
-	// 如果所有字体都加载失败，使用默认字体
-	if loadErr != nil {
-		g.LoadFontFace("", te.FontSize)
+// normalizeNewlines 将"\r\n"和单独的"\r"统一为"\n"
+func normalizeNewlines(s string) string {
+	if !strings.Contains(s, "\r") {
+		return s
 	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
 
-	// 初始化行集合
-	var lines []string
-
-	// 仅当设置了最大行宽时才进行换行处理
-	if te.MaxLineWidth > 0 {
-		runes := []rune(te.Text)
-		if len(runes) == 0 {
-			return 0
-		}
-
-		currentLine := []rune{}
-		// 避免变量 shadow，重用之前声明的 lines 变量，移除重复声明
-		// lines := []string{} 这行代码移除，因为之前已经声明过 lines 变量
+// wrapParagraph 对不含换行符的一段文本自动换行，未设置最大行宽时整段作为一行
+func (te *TextElement) wrapParagraph(g *gg.Context, paragraph string) []string {
+	if te.MaxLineWidth <= 0 {
+		return []string{paragraph}
+	}
 
-		for _, r := range runes {
-			testLine := append(currentLine, r)
-			width, _ := g.MeasureString(string(testLine))
+	// 将文本转换为rune切片处理中文
+	runes := []rune(paragraph)
+	if len(runes) == 0 {
+		return []string{paragraph}
+	}
 
-			if width > float64(te.MaxLineWidth) && len(currentLine) > 0 {
-				lines = append(lines, string(currentLine))
-				currentLine = []rune{r}
-			} else {
-				currentLine = testLine
+	currentLine := []rune{}
+	lines := []string{}
+
+	// 按字符逐个添加，判断是否超出最大宽度
+	for _, r := range runes {
+		// 尝试添加当前字符
+		testLine := append(currentLine, r)
+		width := te.lineWidth(g, string(testLine))
+
+		// 如果超出最大宽度且当前行不为空，则换行
+		if width > float64(te.MaxLineWidth) && len(currentLine) > 0 {
+			nextLine := []rune{r} // 新行从当前字符开始
+			// 避头尾规则：行首不能是闭合标点，行尾不能是开启标点，将上一行末尾字符移到新行
+			for len(currentLine) > 1 && (isLineStartProhibited(nextLine[0]) || isLineEndProhibited(currentLine[len(currentLine)-1])) {
+				nextLine = append([]rune{currentLine[len(currentLine)-1]}, nextLine...)
+				currentLine = currentLine[:len(currentLine)-1]
 			}
-		}
-		if len(currentLine) > 0 {
 			lines = append(lines, string(currentLine))
+			currentLine = nextLine
+		} else {
+			currentLine = testLine
 		}
-
-		// 应用最大行数限制
-		if te.MaxLineCount > 0 && len(lines) > te.MaxLineCount {
-			lines = lines[:te.MaxLineCount]
-		}
-	} else {
-		// 不换行，整段文本作为一行
-		lines = []string{te.Text}
 	}
-
-	if te.MaxLineCount > 0 && len(lines) > te.MaxLineCount {
-		lines = lines[:te.MaxLineCount]
+	// 添加最后一行
+	if len(currentLine) > 0 {
+		lines = append(lines, string(currentLine))
 	}
+	return lines
+}
 
-	maxWidth := 0.0
+// lineStartProhibited 不能出现在行首的字符（闭合标点、小写假名等）
+const lineStartProhibited = "，。、；：？！）】」』》〉〕］｝”’…‥—～·・ー々ぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶ,.;:?!)]}%"
 
-	for _, line := range lines {
-		width, _ := g.MeasureString(line)
-		if width > maxWidth {
-			maxWidth = width
-		}
+// lineEndProhibited 不能出现在行尾的字符（开启标点、货币符号等）
+const lineEndProhibited = "（【「『《〈〔［｛“‘([{$￥＄"
 
+// isLineStartProhibited 判断字符是否不能位于行首
+func isLineStartProhibited(r rune) bool {
+	return strings.ContainsRune(lineStartProhibited, r)
+}
+
+// isLineEndProhibited 判断字符是否不能位于行尾
+func isLineEndProhibited(r rune) bool {
+	return strings.ContainsRune(lineEndProhibited, r)
+}
+
+// appendEllipsis 为被截断的最后一行追加省略后缀，必要时删除行尾字符使其不超出最大行宽
+func (te *TextElement) appendEllipsis(g *gg.Context, line string) string {
+	runes := []rune(line)
+	for te.MaxLineWidth > 0 && len(runes) > 0 {
+		if te.lineWidth(g, string(runes)+te.Ellipsis) <= float64(te.MaxLineWidth) {
+			break
+		}
+		runes = runes[:len(runes)-1]
 	}
+	return string(runes) + te.Ellipsis
+}
 
-	return maxWidth
+// lineX 根据对齐方式计算单行文本的起始X坐标
+// 对齐以MaxLineWidth为参考宽度，未设置最大行宽时始终左对齐
+func (te *TextElement) lineX(lineWidth float64) float64 {
+	x := float64(te.X)
+	if te.MaxLineWidth <= 0 {
+		return x
+	}
+	switch te.Alignment {
+	case AlignCenter:
+		return x + (float64(te.MaxLineWidth)-lineWidth)/2
+	case AlignRight:
+		return x + float64(te.MaxLineWidth) - lineWidth
+	default:
+		return x
+	}
 }
 
 // Draw 实现CombineElement接口，绘制文本元素并支持自动换行
+// 旋转时先在以(X, Y)为原点的局部坐标系中完成排版，再整体绕(X, Y)旋转
 func (te *TextElement) Draw(g *gg.Context, canvasWidth int) {
+	if te.Transparency >= 255 {
+		return
+	}
+	if te.Transparency > 0 {
+		te.drawTranslucent(g, canvasWidth)
+		return
+	}
+	if te.Gradient != nil {
+		te.drawGradient(g, canvasWidth)
+		return
+	}
+
 	g.Push()
 	defer g.Pop()
 
-	g.SetColor(te.Color)
-	// 字体加载逻辑：尝试加载自定义字体，失败时降级使用系统字体
-	customFonts := te.FontPaths
-	if customFonts == nil {
-		customFonts = []string{}
-	}
-	systemFonts := []string{"Alibaba-PuHuiTi-Medium.ttf", "/Library/Fonts/Arial.ttf", "/System/Library/Fonts/PingFang.ttc"}
-	fonts := append(customFonts, systemFonts...)
-	fontLoaded := false
-	for _, fontPath := range fonts {
-		if err := g.LoadFontFace(fontPath, te.FontSize); err == nil {
-			fontLoaded = true
-			break
-		}
+	if te.Rotate != 0 {
+		g.RotateAbout(gg.Radians(te.Rotate), float64(te.X), float64(te.Y))
 	}
-	if !fontLoaded {
-		g.LoadFontFace("", te.FontSize) // 使用gg默认字体作为最后的备选
+	if te.Anchor != AnchorBaselineLeft {
+		g.Translate(te.anchorOffset(te.measure()))
 	}
 
-	// 处理旋转文本
-	if te.Rotate != 0 {
-		g.Translate(float64(te.X), float64(te.Y))
-		g.Rotate(gg.Radians(te.Rotate))
-		g.DrawString(te.Text, 0, 0)
-		// 旋转文本的删除线暂不支持
-	} else {
-		// 自动换行逻辑：仅当设置了最大行宽时启用
-		if te.MaxLineWidth > 0 {
-			// 将文本转换为rune切片处理中文
-			runes := []rune(te.Text)
-			if len(runes) == 0 {
-				g.DrawString(te.Text, float64(te.X), float64(te.Y))
-
-				// 绘制删除线
-				if te.StrikeThrough {
-					width := te.GetWidth()
-					strikeY := float64(te.Y) - te.FontSize*0.4
-					g.DrawLine(float64(te.X), strikeY, float64(te.X)+width, strikeY)
-					g.Stroke()
-				}
-				return
-			}
-
-			// 计算行高：优先使用自定义行高，未设置时使用1.5倍字体大小
-			lineHeight := te.LineHeight
-			if lineHeight <= 0 {
-				lineHeight = te.FontSize * 1.5
-			}
+	if len(te.Spans) > 0 {
+		te.drawSpans(g)
+		return
+	}
 
-			currentLine := []rune{}
-			lines := []string{}
-
-			// 按字符逐个添加，判断是否超出最大宽度
-			for _, r := range runes {
-				// 尝试添加当前字符
-				testLine := append(currentLine, r)
-				width, _ := g.MeasureString(string(testLine))
-
-				// 如果超出最大宽度且当前行不为空，则换行
-				if width > float64(te.MaxLineWidth) && len(currentLine) > 0 {
-					lines = append(lines, string(currentLine))
-					currentLine = []rune{r} // 新行从当前字符开始
-				} else {
-					currentLine = testLine
-				}
-			}
-			// 添加最后一行
-			if len(currentLine) > 0 {
-				lines = append(lines, string(currentLine))
-			}
+	g.SetColor(te.textColor())
+	// 字体加载逻辑：尝试加载自定义字体，失败时降级使用系统字体
+	style := te.loadFont(g)
 
-			// 应用最大行数限制：截断超出部分
-			if te.MaxLineCount > 0 && len(lines) > te.MaxLineCount {
-				lines = lines[:te.MaxLineCount]
-			}
+	if te.Vertical {
+		te.drawVertical(g, style)
+		return
+	}
 
-			// 绘制所有文本行：默认左对齐，按行高偏移Y坐标
-			for i, line := range lines {
-				y := float64(te.Y) + float64(i)*lineHeight
-				g.DrawString(line, float64(te.X), y)
-
-				// 绘制删除线
-				if te.StrikeThrough {
-					width, _ := g.MeasureString(line)
-					strikeY := y - te.FontSize*0.4 // 调整此值以垂直居中删除线
-					g.SetLineWidth(1.0)
-					g.DrawLine(float64(te.X), strikeY, float64(te.X)+width, strikeY)
-					g.Stroke()
-				}
+	// 多行逻辑：设置了最大行宽或文本包含换行符时按行绘制
+	if lines, ends := te.wrapParagraphs(g); te.MaxLineWidth > 0 || len(lines) > 1 {
+		lineHeight := te.lineHeight()
+
+		// 绘制所有文本行：按对齐方式计算X坐标，按行高偏移Y坐标
+		for i, line := range lines {
+			width := te.lineWidth(g, line)
+			x := te.lineX(width)
+			y := float64(te.Y) + float64(i)*lineHeight
+			if te.justified(line, ends[i]) {
+				width = te.drawJustified(g, line, x, y, style)
+			} else {
+				te.drawLine(g, line, x, y, style)
 			}
-		} else {
-			// 不启用自动换行：直接绘制完整文本
-			g.DrawString(te.Text, float64(te.X), float64(te.Y))
 
 			// 绘制删除线
 			if te.StrikeThrough {
-				width, _ := g.MeasureString(te.Text)
-				strikeY := float64(te.Y) - te.FontSize*0.4
-				g.SetLineWidth(2.0)
-				g.DrawLine(float64(te.X), strikeY, float64(te.X)+width, strikeY)
+				strikeY := y - te.FontSize*0.4 // 调整此值以垂直居中删除线
+				g.SetLineWidth(1.0)
+				g.DrawLine(x, strikeY, x+width, strikeY)
 				g.Stroke()
 			}
 		}
+	} else {
+		// 不启用自动换行：直接绘制完整文本
+		te.drawLine(g, te.Text, float64(te.X), float64(te.Y), style)
+
+		// 绘制删除线
+		if te.StrikeThrough {
+			width := te.lineWidth(g, te.Text)
+			strikeY := float64(te.Y) - te.FontSize*0.4
+			g.SetLineWidth(2.0)
+			g.DrawLine(float64(te.X), strikeY, float64(te.X)+width, strikeY)
+			g.Stroke()
+		}
 	}
 }
 
@@ -541,8 +807,8 @@ func (re *RectangleElement) Draw(g *gg.Context, canvasWidth int) {
 	defer g.Pop()
 
 	g.SetColor(re.Color)
-	if re.RoundCorner > 0 {
-		g.DrawRoundedRectangle(float64(re.X), float64(re.Y), float64(re.Width), float64(re.Height), float64(re.RoundCorner))
+	if radii := cornerRadii(re.RoundCorner, re.CornerRadii, float64(re.Width), float64(re.Height)); rounded(radii) {
+		drawRoundedRect(g, float64(re.X), float64(re.Y), float64(re.Width), float64(re.Height), radii)
 	} else {
 		g.DrawRectangle(float64(re.X), float64(re.Y), float64(re.Width), float64(re.Height))
 	}
@@ -1,10 +1,19 @@
 package imgcombine
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"image"
 	"image/color"
+	"image/png"
+	"math"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
 )
 
 // TestSimpleCombine 测试简单图片合成功能
@@ -272,3 +281,858 @@ func TestFullFunctionality(t *testing.T) {
 		t.Error("完整功能测试输出文件未生成")
 	}
 }
+
+// rgb 返回颜色的8位RGB分量，便于断言像素颜色
+func rgb(c color.Color) [3]uint32 {
+	r, g, b, _ := c.RGBA()
+	return [3]uint32{r >> 8, g >> 8, b >> 8}
+}
+
+// inkBounds 返回区域内非白色像素的水平范围，用于断言文本的实际绘制位置
+func inkBounds(img image.Image, rect image.Rectangle) (minX, maxX int, ok bool) {
+	minX, maxX = rect.Max.X, rect.Min.X-1
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if rgb(img.At(x, y)) != [3]uint32{255, 255, 255} {
+				if x < minX {
+					minX = x
+				}
+				if x > maxX {
+					maxX = x
+				}
+			}
+		}
+	}
+	return minX, maxX, maxX >= minX
+}
+
+// TestTextAlignment 测试文本在MaxLineWidth内的水平对齐
+func TestTextAlignment(t *testing.T) {
+	for _, tc := range []struct {
+		align   TextAlign
+		check   func(minX, maxX int) bool
+		explain string
+	}{
+		{AlignLeft, func(minX, maxX int) bool { return minX <= 53 }, "左对齐应贴近X"},
+		{AlignCenter, func(minX, maxX int) bool { return abs((minX+maxX)/2-200) <= 3 }, "居中应以X+MaxLineWidth/2为中心"},
+		{AlignRight, func(minX, maxX int) bool { return maxX >= 345 && maxX <= 350 }, "右对齐应贴近X+MaxLineWidth"},
+	} {
+		combiner := NewImageCombiner(400, 100)
+		text := combiner.AddTextElement("Align", 24, 50, 50)
+		text.MaxLineWidth = 300
+		text.Alignment = tc.align
+
+		img, err := combiner.Combine()
+		if err != nil {
+			t.Fatalf("合成失败: %v", err)
+		}
+		minX, maxX, ok := inkBounds(img, img.Bounds())
+		if !ok || !tc.check(minX, maxX) {
+			t.Errorf("%s: %s，实际范围 [%d, %d]", tc.align, tc.explain, minX, maxX)
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// TestTextElementFont 测试元素级字体文件与字体数据
+func TestTextElementFont(t *testing.T) {
+	fontPath := "../Alibaba-PuHuiTi-Medium.ttf"
+	fontBytes, err := os.ReadFile(fontPath)
+	if err != nil {
+		t.Fatalf("读取字体失败: %v", err)
+	}
+
+	combiner := NewImageCombiner(400, 100)
+	byPath := combiner.AddTextElement("中文", 40, 0, 50)
+	byPath.FontPath = fontPath
+	byBytes := combiner.AddTextElement("中文", 40, 0, 50)
+	byBytes.FontBytes = fontBytes
+	byBytes.FontPath = "/not/exist.ttf"
+
+	// 中文字形宽度接近字号，明显大于内置点阵字体
+	for name, text := range map[string]*TextElement{"FontPath": byPath, "FontBytes": byBytes} {
+		if width := text.GetWidth(); width < 70 || width > 90 {
+			t.Errorf("%s字体未生效，宽度 %.1f", name, width)
+		}
+	}
+	if byPath.GetWidth() != byBytes.GetWidth() {
+		t.Errorf("同一字体的测量结果应一致: %.1f != %.1f", byPath.GetWidth(), byBytes.GetWidth())
+	}
+}
+
+// TestTextEllipsis 测试超出最大行数时追加省略号且不超出行宽
+func TestTextEllipsis(t *testing.T) {
+	combiner := NewImageCombiner(400, 200)
+	text := combiner.AddTextElement("苏格拉底说：如果没有那个桌子，可能就没有那个水壶，也就没有这张海报", 30, 10, 50)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.MaxLineWidth = 200
+	text.MaxLineCount = 2
+	text.Ellipsis = "…"
+
+	g := gg.NewContext(1, 1)
+	text.loadFont(g)
+	lines := text.wrapLines(g)
+	if len(lines) != 2 {
+		t.Fatalf("行数错误: %d", len(lines))
+	}
+	if !strings.HasSuffix(lines[1], "…") {
+		t.Errorf("最后一行应以省略号结尾: %s", lines[1])
+	}
+	for _, line := range lines {
+		if width, _ := g.MeasureString(line); width > 200 {
+			t.Errorf("行宽超出限制: %s %.1f", line, width)
+		}
+	}
+
+	// 未被截断时不追加
+	text.MaxLineCount = 10
+	lines = text.wrapLines(g)
+	if strings.HasSuffix(lines[len(lines)-1], "…") {
+		t.Errorf("未截断时不应追加省略号: %s", lines[len(lines)-1])
+	}
+}
+
+// TestTextKinsoku 测试换行遵循避头尾规则
+func TestTextKinsoku(t *testing.T) {
+	text := &TextElement{
+		Text:     "一二三四五，六七八九十（一二三四）",
+		FontSize: 20,
+		FontPath: "../Alibaba-PuHuiTi-Medium.ttf",
+	}
+	g := gg.NewContext(1, 1)
+	text.loadFont(g)
+	charWidth, _ := g.MeasureString("一")
+
+	// 行宽恰好容纳5个字符：逗号本应落在第二行行首
+	text.MaxLineWidth = int(charWidth*5) + 1
+	lines := text.wrapLines(g)
+	for i, line := range lines {
+		runes := []rune(line)
+		if i > 0 && isLineStartProhibited(runes[0]) {
+			t.Errorf("第%d行以闭合标点开头: %s", i+1, line)
+		}
+		if i < len(lines)-1 && isLineEndProhibited(runes[len(runes)-1]) {
+			t.Errorf("第%d行以开启标点结尾: %s", i+1, line)
+		}
+	}
+	if strings.Join(lines, "") != text.Text {
+		t.Errorf("换行后文本内容不应变化: %v", lines)
+	}
+	if lines[0] != "一二三四" || !strings.HasPrefix(lines[1], "五，") {
+		t.Errorf("逗号前的字符应随逗号移到下一行: %v", lines)
+	}
+}
+
+// TestTextSpans 测试富文本片段参与同一次换行并按各自样式绘制
+func TestTextSpans(t *testing.T) {
+	gray := color.RGBA{150, 150, 150, 255}
+	red := color.RGBA{255, 0, 0, 255}
+
+	combiner := NewImageCombiner(300, 120)
+	text := combiner.AddTextElement("", 20, 10, 40)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.Spans = []TextSpan{
+		{Text: "原价 "},
+		{Text: "￥1290", Color: gray, StrikeThrough: true},
+		{Text: " 现价 "},
+		{Text: "￥999", Color: red, FontSize: 30, Underline: true},
+	}
+
+	// 宽度等于各片段按自身字号测量的宽度之和
+	want := 0.0
+	for _, span := range text.Spans {
+		size := span.FontSize
+		if size == 0 {
+			size = text.FontSize
+		}
+		part := &TextElement{Text: span.Text, FontSize: size, FontPath: text.FontPath}
+		want += part.GetWidth()
+	}
+	if got := text.GetWidth(); abs(int(got-want)) > 1 {
+		t.Errorf("富文本宽度 %.1f，期望 %.1f", got, want)
+	}
+
+	// 窄行宽时换行，且不改变文本内容
+	text.MaxLineWidth = int(want / 2)
+	lines := text.layoutSpans()
+	if len(lines) < 2 {
+		t.Fatalf("富文本应换行，实际 %d 行", len(lines))
+	}
+	var joined strings.Builder
+	for _, line := range lines {
+		if line.width > float64(text.MaxLineWidth) {
+			t.Errorf("行宽 %.1f 超出最大行宽 %d", line.width, text.MaxLineWidth)
+		}
+		for _, run := range line.runs {
+			joined.WriteString(string(run.text))
+		}
+	}
+	if joined.String() != "原价 ￥1290 现价 ￥999" {
+		t.Errorf("换行后文本内容不应变化: %s", joined.String())
+	}
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	hasRed := false
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y && !hasRed; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if c := rgb(img.At(x, y)); c[0] > 200 && c[1] < 60 && c[2] < 60 {
+				hasRed = true
+				break
+			}
+		}
+	}
+	if !hasRed {
+		t.Error("片段颜色未生效")
+	}
+}
+
+// TestTextVertical 测试竖排文本的换列、截断和绘制方向
+func TestTextVertical(t *testing.T) {
+	text := &TextElement{
+		Text:          "白日依山尽黄河入海流，欲穷千里目",
+		FontSize:      20,
+		Vertical:      true,
+		MaxLineHeight: 100,
+		FontPath:      "../Alibaba-PuHuiTi-Medium.ttf",
+	}
+
+	// 每列5个字符，逗号不能位于列首
+	columns := text.verticalColumns()
+	if len(columns) != 4 || string(columns[0]) != "白日依山尽" || string(columns[1]) != "黄河入海" || string(columns[2]) != "流，欲穷千" {
+		t.Errorf("竖排换列错误: %q", columns)
+	}
+	if want := 3*30.0 + 20; text.GetWidth() != want {
+		t.Errorf("竖排宽度 %.1f，期望 %.1f", text.GetWidth(), want)
+	}
+
+	text.MaxColumnCount = 2
+	text.Ellipsis = "…"
+	columns = text.verticalColumns()
+	if len(columns) != 2 || string(columns[1]) != "黄河入海…" {
+		t.Errorf("竖排截断错误: %q", columns)
+	}
+
+	// 单列文本从第一列右侧起向下排列
+	combiner := NewImageCombiner(100, 200)
+	couplet := combiner.AddTextElement("春回大地", 30, 40, 20)
+	couplet.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	couplet.Vertical = true
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	minX, maxX, ok := inkBounds(img, img.Bounds())
+	if !ok || minX < 35 || maxX > 75 {
+		t.Errorf("竖排文本应位于单列内，实际横向范围 %d-%d", minX, maxX)
+	}
+	if _, _, ok := inkBounds(img, image.Rect(0, 120, 100, 140)); !ok {
+		t.Error("第四个字符应绘制在第一列下方")
+	}
+}
+
+// TestTextRotatedLayout 测试旋转文本同样支持自动换行与删除线
+func TestTextRotatedLayout(t *testing.T) {
+	combiner := NewImageCombiner(300, 300)
+	text := combiner.AddTextElement("一二三四五六七八九十一二", 20, 200, 20)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.MaxLineWidth = 60
+	text.StrikeThrough = true
+	text.Rotate = 90
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+
+	// 旋转90度后各行从右向左排列，每行沿纵向不超过最大行宽
+	minX, maxX, ok := inkBounds(img, img.Bounds())
+	if !ok {
+		t.Fatal("未绘制文本")
+	}
+	if maxX-minX < 3*30 {
+		t.Errorf("旋转后应有4行沿横向排列，实际横向范围 %d-%d", minX, maxX)
+	}
+	if _, _, ok := inkBounds(img, image.Rect(0, 20+text.MaxLineWidth+5, 300, 300)); ok {
+		t.Error("旋转后文本不应超出最大行宽")
+	}
+}
+
+// TestTextMeasure 测试文本测量结果与换行、绘制一致
+func TestTextMeasure(t *testing.T) {
+	combiner := NewImageCombiner(400, 300)
+	text := combiner.AddTextElement("苏格拉底说：如果没有那个桌子，可能就没有那个水壶", 20, 10, 40)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.MaxLineWidth = 150
+	text.LineHeight = 30
+
+	m := text.Measure()
+	g := gg.NewContext(1, 1)
+	text.loadFont(g)
+	if lines := text.wrapLines(g); strings.Join(m.Lines, "|") != strings.Join(lines, "|") {
+		t.Errorf("测量的行与换行结果不一致: %v != %v", m.Lines, lines)
+	}
+	if len(m.LineWidths) != len(m.Lines) || m.Width != text.GetWidth() || m.Width > 150 {
+		t.Errorf("行宽测量错误: %v，最大 %.1f", m.LineWidths, m.Width)
+	}
+	if want := float64(len(m.Lines)-1)*30 + 20; m.Height < want || m.Height > want+10 || text.GetHeight() != m.Height {
+		t.Errorf("文本高度 %.1f，期望约 %.1f", m.Height, want)
+	}
+
+	// 绘制结果位于测量的外接矩形内
+	img, _ := combiner.Combine()
+	box := image.Rect(int(m.Left)-1, int(m.Top)-1, int(m.Left+m.Width)+2, int(m.Bottom())+2)
+	minX, maxX, _ := inkBounds(img, img.Bounds())
+	if minX < box.Min.X || maxX >= box.Max.X {
+		t.Errorf("横向绘制范围 %d-%d 超出测量范围 %v", minX, maxX, box)
+	}
+	if _, _, ok := inkBounds(img, image.Rect(0, box.Max.Y, 400, 300)); ok {
+		t.Errorf("绘制内容超出测量的底边 %d", box.Max.Y)
+	}
+}
+
+func TestTextJustify(t *testing.T) {
+	for _, content := range []string{
+		"苏格拉底说：如果没有那个桌子，可能就没有那个水壶",
+		"the quick brown fox jumps over the lazy dog again",
+	} {
+		combiner := NewImageCombiner(300, 200)
+		text := combiner.AddTextElement(content, 20, 10, 40)
+		text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+		text.MaxLineWidth = 200
+		text.LineHeight = 30
+		text.Alignment = AlignJustify
+
+		m := text.Measure()
+		if len(m.Lines) < 2 {
+			t.Fatalf("%q 应换行，实际 %v", content, m.Lines)
+		}
+		img, _ := combiner.Combine()
+		for i := range m.Lines {
+			top := 40 + i*30 - 20
+			minX, maxX, ok := inkBounds(img, image.Rect(0, top, 300, top+30))
+			if !ok {
+				t.Fatalf("%q 第%d行未绘制", content, i)
+			}
+			last := i == len(m.Lines)-1
+			if !last && (minX > 12 || maxX < 205) {
+				t.Errorf("%q 第%d行应填满行宽，实际 %d-%d", content, i, minX, maxX)
+			}
+			if last && m.LineWidths[i] >= 200 {
+				t.Errorf("%q 最后一行不应拉伸，宽度 %.1f", content, m.LineWidths[i])
+			}
+			if maxX > 10+200+2 {
+				t.Errorf("%q 第%d行超出行宽: %d", content, i, maxX)
+			}
+		}
+	}
+}
+
+// TestTextNewlines 测试换行符作为强制换行，与自动换行和最大行数限制配合
+func TestTextNewlines(t *testing.T) {
+	combiner := NewImageCombiner(300, 300)
+	text := combiner.AddTextElement("标题\r\n\n苏格拉底说：如果没有那个桌子，可能就没有那个水壶", 20, 10, 40)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+
+	// 未设置最大行宽时仅按换行符分行
+	if lines := text.Measure().Lines; strings.Join(lines, "|") != "标题||苏格拉底说：如果没有那个桌子，可能就没有那个水壶" {
+		t.Errorf("未设置行宽时分行错误: %q", lines)
+	}
+
+	text.MaxLineWidth = 150
+	lines := text.Measure().Lines
+	if len(lines) < 4 || lines[0] != "标题" || lines[1] != "" || !strings.HasPrefix(lines[2], "苏格拉底") {
+		t.Errorf("换行符与自动换行组合错误: %q", lines)
+	}
+
+	text.MaxLineCount = 3
+	text.Ellipsis = "…"
+	if lines := text.Measure().Lines; len(lines) != 3 || !strings.HasSuffix(lines[2], "…") {
+		t.Errorf("最大行数应计入换行符产生的行: %q", lines)
+	}
+
+	// 两端对齐时段落末行不拉伸
+	text.MaxLineCount = 0
+	text.Alignment = AlignJustify
+	if m := text.Measure(); m.LineWidths[0] >= 150 {
+		t.Errorf("段落末行不应拉伸，宽度 %.1f", m.LineWidths[0])
+	}
+
+	spans := &TextElement{FontSize: 20, Spans: []TextSpan{{Text: "A\n"}, {Text: "\nB"}}}
+	if lines := spans.Measure().Lines; strings.Join(lines, "|") != "A||B" {
+		t.Errorf("富文本换行错误: %q", lines)
+	}
+
+	vertical := &TextElement{Text: "春眠\n不觉晓", FontSize: 20, Vertical: true}
+	if columns := vertical.Measure().Lines; strings.Join(columns, "|") != "春眠|不觉晓" {
+		t.Errorf("竖排换列错误: %q", columns)
+	}
+}
+
+// TestTextAnchor 测试锚点定位：测量的外接矩形与绘制结果都以锚点对齐(X, Y)
+func TestTextAnchor(t *testing.T) {
+	for _, anchor := range []TextAnchor{AnchorCenter, AnchorBottomRight, AnchorTopLeft, AnchorBaselineRight} {
+		combiner := NewImageCombiner(400, 300)
+		text := combiner.AddTextElement("苏格拉底说：如果没有那个桌子，可能就没有那个水壶", 20, 200, 150)
+		text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+		text.MaxLineWidth = 150
+		text.Anchor = anchor
+
+		m := text.Measure()
+		ax, ay := anchor.anchorFactors()
+		if x := m.Left + m.Width*ax; math.Abs(x-200) > 0.5 {
+			t.Errorf("%s: 锚点X为 %.1f，期望200", anchor, x)
+		}
+		if ay >= 0 {
+			if y := m.Top + m.Height*ay; math.Abs(y-150) > 0.5 {
+				t.Errorf("%s: 锚点Y为 %.1f，期望150", anchor, y)
+			}
+		}
+
+		img, _ := combiner.Combine()
+		minX, maxX, _ := inkBounds(img, img.Bounds())
+		if float64(minX) < m.Left-2 || float64(maxX) > m.Left+m.Width+2 {
+			t.Errorf("%s: 绘制范围 %d-%d 与测量范围 %.1f-%.1f 不一致", anchor, minX, maxX, m.Left, m.Left+m.Width)
+		}
+	}
+}
+
+// TestTextTabStops 测试制表符按制表位对齐成列
+func TestTextTabStops(t *testing.T) {
+	combiner := NewImageCombiner(400, 200)
+	text := combiner.AddTextElement("价格:\t￥999\n运费说明:\t免运费", 20, 10, 40)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.LineHeight = 40
+	text.TabStops = []float64{120}
+
+	g := gg.NewContext(1, 1)
+	text.loadFont(g)
+	m := text.Measure()
+	for i, value := range []string{"￥999", "免运费"} {
+		width, _ := g.MeasureString(value)
+		if math.Abs(m.LineWidths[i]-(120+width)) > 0.5 {
+			t.Errorf("第%d行宽度 %.1f，期望 %.1f", i, m.LineWidths[i], 120+width)
+		}
+	}
+
+	// 两行的值都从制表位开始绘制
+	img, _ := combiner.Combine()
+	for i := 0; i < 2; i++ {
+		top := 40 + i*40 - 20
+		minX, _, ok := inkBounds(img, image.Rect(10+100, top, 400, top+28))
+		if !ok || minX < 10+120-1 || minX > 10+120+4 {
+			t.Errorf("第%d行的值应从制表位 %d 开始，实际 %d", i, 10+120, minX)
+		}
+	}
+
+	// 超出制表位后按4倍字号的间隔继续
+	plain := &TextElement{FontSize: 10, TabStops: []float64{15}}
+	for offset, want := range map[float64]float64{0: 15, 15: 55, 30: 55, 60: 95} {
+		if got := plain.nextTabStop(offset); got != want {
+			t.Errorf("nextTabStop(%.0f) = %.0f，期望 %.0f", offset, got, want)
+		}
+	}
+}
+
+// TestTextMaxHeight 测试按最大高度截断文本
+func TestTextMaxHeight(t *testing.T) {
+	content := "苏格拉底说：如果没有那个桌子，可能就没有那个水壶，也就没有那个杯子"
+	combiner := NewImageCombiner(300, 300)
+	text := combiner.AddTextElement(content, 20, 10, 40)
+	text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+	text.MaxLineWidth = 150
+	text.LineHeight = 30
+	text.Ellipsis = "…"
+
+	full := len(text.Measure().Lines)
+	text.MaxHeight = 75
+	m := text.Measure()
+	if len(m.Lines) >= full || m.Height > 75 || !strings.HasSuffix(m.Lines[len(m.Lines)-1], "…") {
+		t.Errorf("应截断到75像素内并追加省略号: %q 高度 %.1f", m.Lines, m.Height)
+	}
+	// 再多一行就会超出
+	if m.Height+30 <= 75 {
+		t.Errorf("截断过早：%d 行，高度 %.1f", len(m.Lines), m.Height)
+	}
+
+	text.MaxLineCount = 1
+	if lines := text.Measure().Lines; len(lines) != 1 {
+		t.Errorf("MaxLineCount更严格时应取1行，实际 %d 行", len(lines))
+	}
+
+	spans := &TextElement{FontSize: 20, MaxLineWidth: 150, LineHeight: 30, MaxHeight: 75,
+		FontPath: "../Alibaba-PuHuiTi-Medium.ttf", Spans: []TextSpan{{Text: content}}}
+	if m := spans.Measure(); m.Height > 75 || len(m.Lines) >= full {
+		t.Errorf("富文本应按最大高度截断: %d 行，高度 %.1f", len(m.Lines), m.Height)
+	}
+}
+
+// TestTextCharPositions 测试逐字位置与整体绘制一致，覆盖字距调整、换行、两端对齐和富文本
+func TestTextCharPositions(t *testing.T) {
+	cases := map[string]*TextElement{
+		"kerning": {Text: "AVATAR Wave, To", FontSize: 30, X: 10, Y: 50},
+		"wrap":    {Text: "苏格拉底说：如果没有那个桌子，可能就没有那个水壶", FontSize: 20, X: 10, Y: 40, MaxLineWidth: 200, LineHeight: 30, Alignment: AlignCenter},
+		"justify": {Text: "the quick brown fox jumps over the lazy dog again", FontSize: 20, X: 10, Y: 40, MaxLineWidth: 200, LineHeight: 30, Alignment: AlignJustify},
+		"tabs":    {Text: "名称\t数量\nA\t1", FontSize: 20, X: 10, Y: 40, TabStops: []float64{120}},
+		"anchor":  {Text: "居中", FontSize: 30, X: 150, Y: 100, Anchor: AnchorCenter},
+		"spans":   {FontSize: 20, X: 10, Y: 40, Spans: []TextSpan{{Text: "Big ", FontSize: 36}, {Text: "small"}}},
+	}
+	for name, text := range cases {
+		text.FontPath = "../Alibaba-PuHuiTi-Medium.ttf"
+		text.Color = color.Black
+
+		whole := NewImageCombiner(300, 200)
+		whole.AddElement(text)
+		want, _ := whole.Combine()
+
+		// 按位置逐字绘制
+		g := gg.NewContext(300, 200)
+		g.SetColor(color.White)
+		g.Clear()
+		g.SetColor(color.Black)
+		positions := text.CharPositions()
+		for _, p := range positions {
+			g.SetFontFace(truetype.NewFace(text.resolveFont(), &truetype.Options{Size: p.Size}))
+			g.DrawString(string(p.Rune), p.X, p.Y)
+		}
+		got := g.Image()
+
+		diff := 0
+		for y := 0; y < 200; y++ {
+			for x := 0; x < 300; x++ {
+				a, b := rgb(want.At(x, y)), rgb(got.At(x, y))
+				// 锚点平移为小数时整体绘制会重采样，只统计明显不同的像素
+				if max(a[0], b[0])-min(a[0], b[0]) > 128 {
+					diff++
+				}
+			}
+		}
+		if diff > 10 {
+			t.Errorf("%s: 逐字绘制与整体绘制相差 %d 个像素", name, diff)
+		}
+	}
+
+	// 换行符不计入，每个字符都有位置
+	text := cases["tabs"]
+	if positions := text.CharPositions(); len(positions) != len([]rune("名称\t数量A\t1")) || positions[len(positions)-1].Line != 1 {
+		t.Errorf("字符数或行号错误: %+v", positions)
+	}
+}
+
+// TestImageCrop 测试只缩放和绘制源图的裁剪区域
+func TestImageCrop(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			if x >= 50 && y >= 50 {
+				photo.Set(x, y, color.RGBA{0, 0, 255, 255})
+			} else {
+				photo.Set(x, y, color.RGBA{255, 0, 0, 255})
+			}
+		}
+	}
+
+	combiner := NewImageCombiner(300, 100)
+	combiner.AddElement(&ImageElement{image: photo, ZoomMode: WidthHeight, Width: 100, Height: 100, Alpha: 255,
+		CropX: 50, CropY: 50, CropWidth: 50, CropHeight: 50})
+	combiner.AddElement(&ImageElement{image: photo, X: 150, ZoomMode: Origin, Alpha: 255,
+		CropX: 50, CropY: 50, CropWidth: 80, CropHeight: 80})
+	img, _ := combiner.Combine()
+
+	for _, p := range []image.Point{{2, 2}, {50, 50}, {97, 97}, {152, 2}, {197, 47}} {
+		if c := rgb(img.At(p.X, p.Y)); c[0] > 10 || c[2] < 245 {
+			t.Errorf("%v 应为裁剪区域的蓝色，实际 %v", p, c)
+		}
+	}
+	// 超出图片的裁剪区域被忽略，原尺寸绘制为50x50
+	if c := rgb(img.At(205, 55)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("裁剪区域应截止于图片边界，实际 %v", c)
+	}
+}
+
+// TestImageFlip 测试水平和垂直翻转
+func TestImageFlip(t *testing.T) {
+	// 左上角红色、右上角绿色、左下角蓝色、右下角白色
+	photo := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	photo.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	photo.Set(1, 0, color.RGBA{0, 255, 0, 255})
+	photo.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	photo.Set(1, 1, color.RGBA{255, 255, 255, 255})
+
+	tests := []struct {
+		flipH, flipV bool
+		want         [3]uint32
+	}{
+		{false, false, [3]uint32{255, 0, 0}},
+		{true, false, [3]uint32{0, 255, 0}},
+		{false, true, [3]uint32{0, 0, 255}},
+		{true, true, [3]uint32{255, 255, 255}},
+	}
+	for _, tt := range tests {
+		combiner := NewImageCombiner(2, 2)
+		combiner.AddElement(&ImageElement{image: photo, ZoomMode: Origin, Alpha: 255, FlipH: tt.flipH, FlipV: tt.flipV})
+		img, _ := combiner.Combine()
+		if c := rgb(img.At(0, 0)); c != tt.want {
+			t.Errorf("FlipH=%v FlipV=%v 左上角应为 %v，实际 %v", tt.flipH, tt.flipV, tt.want, c)
+		}
+	}
+}
+
+// TestImageNineSlice 测试九宫格缩放时四角保持原尺寸
+func TestImageNineSlice(t *testing.T) {
+	// 10x10的源图，四角2x2为红色，其余为蓝色
+	photo := solidImage(10, 10, color.RGBA{0, 0, 255, 255}).(*image.RGBA)
+	for _, corner := range []image.Point{{0, 0}, {8, 0}, {0, 8}, {8, 8}} {
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 2; x++ {
+				photo.Set(corner.X+x, corner.Y+y, color.RGBA{255, 0, 0, 255})
+			}
+		}
+	}
+
+	combiner := NewImageCombiner(100, 40)
+	combiner.AddElement(&ImageElement{image: photo, Width: 100, Height: 40, ZoomMode: NineSlice, Alpha: 255, Slice: Insets{2, 2, 2, 2}})
+	img, _ := combiner.Combine()
+
+	checks := []struct {
+		x, y int
+		want [3]uint32
+	}{
+		{1, 1, [3]uint32{255, 0, 0}},
+		{98, 38, [3]uint32{255, 0, 0}},
+		{3, 3, [3]uint32{0, 0, 255}},
+		{50, 1, [3]uint32{0, 0, 255}},
+		{1, 20, [3]uint32{0, 0, 255}},
+	}
+	for _, c := range checks {
+		if got := rgb(img.At(c.x, c.y)); got != c.want {
+			t.Errorf("(%d, %d) 应为 %v，实际 %v", c.x, c.y, c.want, got)
+		}
+	}
+
+	// 目标尺寸小于内边距之和时四角按比例缩小
+	if got := nineSlice(photo, Insets{6, 6, 6, 6}, 4, 4, DefaultFilter).Bounds(); got.Dx() != 4 || got.Dy() != 4 {
+		t.Errorf("九宫格输出尺寸错误: %v", got)
+	}
+}
+
+// TestImageRepeat 测试平铺方向、单元尺寸、间距和错行
+func TestImageRepeat(t *testing.T) {
+	red := [3]uint32{255, 0, 0}
+	white := [3]uint32{255, 255, 255}
+	render := func(configure func(ie *ImageElement)) image.Image {
+		combiner := NewImageCombiner(100, 100)
+		element := &ImageElement{image: solidImage(10, 10, color.RGBA{255, 0, 0, 255}), Width: 100, Height: 100, ZoomMode: Origin, Alpha: 255, TileWidth: 20}
+		configure(element)
+		combiner.AddElement(element)
+		img, _ := combiner.Combine()
+		return img
+	}
+
+	img := render(func(ie *ImageElement) { ie.Repeat = RepeatX })
+	if rgb(img.At(95, 15)) != red || rgb(img.At(5, 25)) != white {
+		t.Error("repeat-x应只平铺第一行")
+	}
+	img = render(func(ie *ImageElement) { ie.Repeat = RepeatY })
+	if rgb(img.At(15, 95)) != red || rgb(img.At(25, 5)) != white {
+		t.Error("repeat-y应只平铺第一列")
+	}
+	img = render(func(ie *ImageElement) { ie.Repeat, ie.TileSpacing = RepeatBoth, 10 })
+	if rgb(img.At(85, 85)) != white || rgb(img.At(65, 65)) != red || rgb(img.At(25, 5)) != white {
+		t.Error("双向平铺应按单元和间距排列")
+	}
+	img = render(func(ie *ImageElement) { ie.Repeat, ie.TileSpacing, ie.TileStagger = RepeatBoth, 10, true })
+	if rgb(img.At(2, 35)) != red || rgb(img.At(10, 35)) != white || rgb(img.At(20, 35)) != red {
+		t.Error("错行时第二行应错开半个单元")
+	}
+}
+
+// TestImageBlur 测试图片缩放后模糊，边缘不会混入透明
+func TestImageBlur(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 50, 50))
+	for y := 0; y < 50; y++ {
+		for x := 0; x < 50; x++ {
+			if x < 25 {
+				photo.Set(x, y, color.RGBA{0, 0, 0, 255})
+			} else {
+				photo.Set(x, y, color.RGBA{255, 255, 255, 255})
+			}
+		}
+	}
+	combiner := NewImageCombiner(100, 100)
+	combiner.AddRectangleElement(0, 0, 100, 100).Color = color.RGBA{255, 0, 0, 255}
+	combiner.AddElement(&ImageElement{image: photo, ZoomMode: WidthHeight, Width: 100, Height: 100, Alpha: 255, Blur: 8})
+	img, _ := combiner.Combine()
+
+	if c := rgb(img.At(50, 50)); c[0] < 90 || c[0] > 165 || c[0] != c[2] {
+		t.Errorf("明暗交界处应模糊为灰色，实际 %v", c)
+	}
+	if c := rgb(img.At(0, 0)); c != [3]uint32{0, 0, 0} {
+		t.Errorf("边缘不应透出背景，实际 %v", c)
+	}
+}
+
+// TestImageBorder 测试描边沿圆形轮廓绘制且位于图片区域内
+func TestImageBorder(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	combiner.AddElement(&ImageElement{
+		image: solidImage(80, 80, color.RGBA{0, 0, 255, 255}), X: 10, Y: 10, ZoomMode: Origin, Alpha: 255,
+		RoundCorner: 40, BorderColor: color.RGBA{255, 0, 0, 255}, BorderWidth: 4,
+	})
+	img, _ := combiner.Combine()
+
+	if c := rgb(img.At(50, 12)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("圆形顶部应为描边颜色，实际 %v", c)
+	}
+	if c := rgb(img.At(50, 20)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("描边内侧应为图片，实际 %v", c)
+	}
+	if c := rgb(img.At(50, 8)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("描边不应超出图片区域，实际 %v", c)
+	}
+	if c := rgb(img.At(14, 14)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("圆形描边的角落应为背景，实际 %v", c)
+	}
+}
+
+// TestCornerRadii 测试四角独立圆角：顶部圆角、底部直角
+func TestCornerRadii(t *testing.T) {
+	combiner := NewImageCombiner(200, 100)
+	rect := combiner.AddRectangleElement(0, 0, 100, 100)
+	rect.Color = color.RGBA{255, 0, 0, 255}
+	rect.RoundCorner = 5
+	rect.CornerRadii = CornerRadii{30, 30, 0, 0}
+	combiner.AddElement(&ImageElement{
+		image: solidImage(100, 100, color.RGBA{0, 0, 255, 255}), X: 100, ZoomMode: Origin, Alpha: 255,
+		CornerRadii: CornerRadii{0, 0, 200, 0},
+	})
+	img, _ := combiner.Combine()
+
+	checks := []struct {
+		x, y int
+		want [3]uint32
+		desc string
+	}{
+		{2, 2, [3]uint32{255, 255, 255}, "矩形左上角应为圆角"},
+		{97, 2, [3]uint32{255, 255, 255}, "矩形右上角应为圆角"},
+		{1, 98, [3]uint32{255, 0, 0}, "矩形左下角应为直角"},
+		{98, 98, [3]uint32{255, 0, 0}, "矩形右下角应为直角，CornerRadii优先于RoundCorner"},
+		{101, 1, [3]uint32{0, 0, 255}, "图片左上角应为直角"},
+		{198, 98, [3]uint32{255, 255, 255}, "图片右下角应为限制到短边一半的圆角"},
+		{101, 98, [3]uint32{0, 0, 255}, "图片左下角应为直角"},
+	}
+	for _, c := range checks {
+		if got := rgb(img.At(c.x, c.y)); got != c.want {
+			t.Errorf("%s，实际 %v", c.desc, got)
+		}
+	}
+}
+
+// TestImageResizeFilter 测试按元素和全局设置缩放插值算法
+func TestImageResizeFilter(t *testing.T) {
+	// 左黑右白的2x1像素画
+	pixel := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	pixel.Set(0, 0, color.Black)
+	pixel.Set(1, 0, color.White)
+	render := func(filter ResizeFilter) image.Image {
+		combiner := NewImageCombiner(16, 8)
+		combiner.AddElement(&ImageElement{image: pixel, Width: 16, Height: 8, ZoomMode: WidthHeight, Alpha: 255, ResizeFilter: filter})
+		img, _ := combiner.Combine()
+		return img
+	}
+
+	img := render(NearestNeighbor)
+	if rgb(img.At(7, 4)) != [3]uint32{0, 0, 0} || rgb(img.At(8, 4)) != [3]uint32{255, 255, 255} {
+		t.Errorf("最近邻缩放应保留硬边缘，实际 %v %v", rgb(img.At(7, 4)), rgb(img.At(8, 4)))
+	}
+	for _, filter := range []ResizeFilter{Bilinear, Bicubic, Lanczos3} {
+		if c := rgb(render(filter).At(7, 4)); c[0] == 0 || c[0] == 255 {
+			t.Errorf("%s 缩放边缘应有过渡，实际 %v", filter, c)
+		}
+	}
+
+	defer func(filter ResizeFilter) { DefaultResizeFilter = filter }(DefaultResizeFilter)
+	DefaultResizeFilter = NearestNeighbor
+	if c := rgb(render(DefaultFilter).At(7, 4)); c != [3]uint32{0, 0, 0} {
+		t.Errorf("未指定时应使用DefaultResizeFilter，实际 %v", c)
+	}
+}
+
+// TestImageSkipResize 测试尺寸不变时跳过缩放，像素原样绘制
+func TestImageSkipResize(t *testing.T) {
+	// 1像素间隔的棋盘格，任何插值都会使其变灰
+	board := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			if (x+y)%2 == 0 {
+				board.Set(x, y, color.Black)
+			} else {
+				board.Set(x, y, color.White)
+			}
+		}
+	}
+
+	// 尺寸相同时不应调用加速后端
+	counting := &countingAccelerator{}
+	RegisterAccelerator(counting)
+	UseAccelerator("counting")
+	defer UseAccelerator("go")
+
+	for _, mode := range []ZoomMode{Origin, WidthHeight} {
+		combiner := NewImageCombiner(10, 10)
+		combiner.AddElement(&ImageElement{image: board, Width: 10, Height: 10, ZoomMode: mode, Alpha: 255})
+		img, _ := combiner.Combine()
+		if rgb(img.At(4, 4)) != [3]uint32{0, 0, 0} || rgb(img.At(5, 4)) != [3]uint32{255, 255, 255} {
+			t.Errorf("%s 模式下应原样绘制，实际 %v %v", mode, rgb(img.At(4, 4)), rgb(img.At(5, 4)))
+		}
+	}
+	if counting.resizes != 0 {
+		t.Errorf("尺寸相同时应跳过缩放，调用次数 %d", counting.resizes)
+	}
+}
+
+// TestAddImageElementFromMemory 测试从已解码图片、字节和Reader添加图片元素
+func TestAddImageElementFromMemory(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, solidImage(10, 10, color.RGBA{0, 0, 255, 255}))
+
+	combiner := NewImageCombiner(30, 10)
+	combiner.AddImageElementFromImage(solidImage(10, 10, color.RGBA{255, 0, 0, 255}), 0, 0, Origin)
+	if _, err := combiner.AddImageElementFromBytes(buf.Bytes(), 10, 0, Origin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := combiner.AddImageElementFromReader(bytes.NewReader(buf.Bytes()), 20, 0, Origin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := combiner.AddImageElementFromBytes([]byte("not an image"), 0, 0, Origin); err == nil {
+		t.Error("无效的图片数据应返回错误")
+	}
+
+	// 内存中的图片内嵌在JSON中，可以重新加载
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, c := range map[string]*ImageCombiner{"原合成器": combiner, "JSON恢复": restored} {
+		img, _ := c.Combine()
+		for x, want := range map[int][3]uint32{5: {255, 0, 0}, 15: {0, 0, 255}, 25: {0, 0, 255}} {
+			if got := rgb(img.At(x, 5)); got != want {
+				t.Errorf("%s: (%d, 5) 应为 %v，实际 %v", name, x, want, got)
+			}
+		}
+	}
+}
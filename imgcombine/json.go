@@ -0,0 +1,434 @@
+package imgcombine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"image/png"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// elementTypes 元素类型注册表，序列化时以类型名标识元素
+var elementTypes = struct {
+	sync.RWMutex
+	byName map[string]func() CombineElement
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]func() CombineElement),
+	byType: make(map[reflect.Type]string),
+}
+
+func init() {
+	RegisterElementType("image", func() CombineElement { return &ImageElement{} })
+	RegisterElementType("text", func() CombineElement { return &TextElement{} })
+	RegisterElementType("rectangle", func() CombineElement { return &RectangleElement{} })
+	RegisterElementType("heatmap", func() CombineElement { return &HeatmapElement{} })
+	RegisterElementType("gauge", func() CombineElement { return &GaugeElement{} })
+	RegisterElementType("timeline", func() CombineElement { return &TimelineElement{} })
+	RegisterElementType("donut", func() CombineElement { return &DonutElement{} })
+	RegisterElementType("confetti", func() CombineElement { return &ConfettiElement{} })
+	RegisterElementType("arc_text", func() CombineElement { return &ArcTextElement{} })
+	RegisterElementType("mockup", func() CombineElement { return &MockupElement{} })
+	RegisterElementType("step", func() CombineElement { return &StepElement{} })
+	RegisterElementType("arrow", func() CombineElement { return &ArrowElement{} })
+	RegisterElementType("highlight", func() CombineElement { return &HighlightElement{} })
+	RegisterElementType("compare", func() CombineElement { return &CompareElement{} })
+	RegisterElementType("path", func() CombineElement { return &PathElement{} })
+}
+
+// RegisterElementType 注册元素类型，使自定义元素可参与JSON序列化
+// newElement返回该类型的零值指针，序列化时保存其全部导出字段
+func RegisterElementType(name string, newElement func() CombineElement) {
+	elementTypes.Lock()
+	defer elementTypes.Unlock()
+	elementTypes.byName[name] = newElement
+	elementTypes.byType[reflect.TypeOf(newElement())] = name
+}
+
+// jsonLoader 反序列化后需要恢复内部状态的元素（如重新加载图片）
+type jsonLoader interface {
+	loadJSON(ic *ImageCombiner) error
+}
+
+// combinerJSON 合成器的JSON结构，字体注册表和审核钩子不参与序列化
+type combinerJSON struct {
+	Width                int             `json:"width"`
+	Height               int             `json:"height"`
+	OutputFormat         OutputFormat    `json:"outputFormat,omitempty"`
+	Quality              int             `json:"quality,omitempty"`
+	FontPaths            []string        `json:"fontPaths,omitempty"`
+	AccessibilitySidecar bool            `json:"accessibilitySidecar,omitempty"`
+	FallbackFonts        []string        `json:"fallbackFonts,omitempty"`
+	InvisibleWatermark   string          `json:"invisibleWatermark,omitempty"`
+	ColorMode            ColorMode       `json:"colorMode,omitempty"`
+	Seed                 *int64          `json:"seed,omitempty"`
+	Theme                json.RawMessage `json:"theme,omitempty"`
+	ColorScheme          ColorScheme     `json:"colorScheme,omitempty"`
+	Elements             []elementJSON   `json:"elements"`
+}
+
+// elementJSON 元素的JSON结构
+type elementJSON struct {
+	Type  string          `json:"type"`
+	Props json.RawMessage `json:"props"`
+	Data  []byte          `json:"data,omitempty"` // 没有来源路径的元素内嵌的数据，如内存图片的PNG编码
+}
+
+// jsonEmbedder 可能没有可重新加载的来源、需要在JSON中内嵌数据的元素
+type jsonEmbedder interface {
+	embedJSON() ([]byte, error) // 返回需要内嵌的数据，有来源可重新加载时返回nil
+	unembedJSON(data []byte) error
+}
+
+// MarshalJSON 将合成器及其元素序列化为JSON，元素类型须已通过RegisterElementType注册
+func (ic *ImageCombiner) MarshalJSON() ([]byte, error) {
+	doc := combinerJSON{
+		Width:                ic.width,
+		Height:               ic.height,
+		OutputFormat:         ic.OutputFormat,
+		Quality:              int(ic.quality*100 + 0.5),
+		FontPaths:            ic.FontPaths,
+		AccessibilitySidecar: ic.AccessibilitySidecar,
+		FallbackFonts:        ic.FallbackFonts,
+		InvisibleWatermark:   ic.InvisibleWatermark,
+		ColorMode:            ic.ColorMode,
+		ColorScheme:          ic.ColorScheme,
+		Elements:             make([]elementJSON, 0, len(ic.elements)),
+	}
+	if ic.seeded {
+		doc.Seed = &ic.seed
+	}
+	if ic.Theme != nil {
+		theme, err := json.Marshal(encodeValue(reflect.ValueOf(ic.Theme)))
+		if err != nil {
+			return nil, fmt.Errorf("marshal theme: %v", err)
+		}
+		doc.Theme = theme
+	}
+
+	elementTypes.RLock()
+	defer elementTypes.RUnlock()
+	for _, element := range ic.elements {
+		name, ok := elementTypes.byType[reflect.TypeOf(element)]
+		if !ok {
+			return nil, fmt.Errorf("element type %T is not registered", element)
+		}
+		props, err := json.Marshal(encodeValue(reflect.ValueOf(element)))
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s element: %v", name, err)
+		}
+		item := elementJSON{Type: name, Props: props}
+		if embedder, ok := element.(jsonEmbedder); ok {
+			if item.Data, err = embedder.embedJSON(); err != nil {
+				return nil, fmt.Errorf("marshal %s element: %v", name, err)
+			}
+		}
+		doc.Elements = append(doc.Elements, item)
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON 从JSON恢复合成器，图片元素会按ImagePath重新加载，内存中的图片从内嵌数据解码
+// 已设置的Fonts、Moderator、BackgroundRemover和Retoucher保持不变
+func (ic *ImageCombiner) UnmarshalJSON(data []byte) error {
+	var doc combinerJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	restored := NewImageCombiner(doc.Width, doc.Height)
+	restored.Fonts = ic.Fonts
+	restored.Moderator = ic.Moderator
+	restored.BackgroundRemover = ic.BackgroundRemover
+	restored.Retoucher = ic.Retoucher
+	if doc.OutputFormat != "" {
+		restored.OutputFormat = doc.OutputFormat
+	}
+	if doc.Quality > 0 {
+		if err := restored.SetQuality(doc.Quality); err != nil {
+			return err
+		}
+	}
+	restored.FontPaths = doc.FontPaths
+	restored.AccessibilitySidecar = doc.AccessibilitySidecar
+	restored.FallbackFonts = doc.FallbackFonts
+	restored.InvisibleWatermark = doc.InvisibleWatermark
+	restored.ColorMode = doc.ColorMode
+	restored.ColorScheme = doc.ColorScheme
+	if doc.Seed != nil {
+		restored.SetSeed(*doc.Seed)
+	}
+	if doc.Theme != nil {
+		if err := decodeValue(doc.Theme, reflect.ValueOf(&restored.Theme).Elem()); err != nil {
+			return fmt.Errorf("theme: %v", err)
+		}
+	}
+
+	for i, item := range doc.Elements {
+		elementTypes.RLock()
+		newElement, ok := elementTypes.byName[item.Type]
+		elementTypes.RUnlock()
+		if !ok {
+			return fmt.Errorf("element %d: unknown type %q", i, item.Type)
+		}
+
+		element := newElement()
+		target := reflect.ValueOf(element)
+		if target.Kind() != reflect.Pointer {
+			return fmt.Errorf("element %d: type %q must be registered as a pointer", i, item.Type)
+		}
+		if err := decodeValue(item.Props, target.Elem()); err != nil {
+			return fmt.Errorf("element %d (%s): %v", i, item.Type, err)
+		}
+		if item.Data != nil {
+			embedder, ok := element.(jsonEmbedder)
+			if !ok {
+				return fmt.Errorf("element %d (%s): unexpected embedded data", i, item.Type)
+			}
+			if err := embedder.unembedJSON(item.Data); err != nil {
+				return fmt.Errorf("element %d (%s): %v", i, item.Type, err)
+			}
+		}
+		if loader, ok := element.(jsonLoader); ok {
+			if err := loader.loadJSON(restored); err != nil {
+				return fmt.Errorf("element %d (%s): %v", i, item.Type, err)
+			}
+		}
+		restored.AddElement(element)
+	}
+
+	*ic = *restored
+	return nil
+}
+
+// embedJSON 没有ImagePath和FallbackName的图片（内存中的图片）以PNG编码内嵌到JSON
+func (ie *ImageElement) embedJSON() ([]byte, error) {
+	if ie.ImagePath != "" || ie.FallbackName != "" || ie.image == nil {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, ie.image); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unembedJSON 解码JSON中内嵌的图片
+func (ie *ImageElement) unembedJSON(data []byte) (err error) {
+	ie.image, err = decodeImage(bytes.NewReader(data))
+	return err
+}
+
+// loadJSON 按ImagePath重新加载图片和遮罩图片，设置了FallbackName时加载失败改用首字母头像
+func (ie *ImageElement) loadJSON(ic *ImageCombiner) error {
+	if ie.Mask != nil {
+		if err := ie.Mask.load(); err != nil {
+			return err
+		}
+	}
+
+	if ie.FallbackName != "" {
+		ie.loadAvatar(ic.FontPaths)
+		return nil
+	}
+	if ie.ImagePath == "" {
+		if ie.image != nil {
+			return nil
+		}
+		return fmt.Errorf("image element has no ImagePath to reload from")
+	}
+
+	var err error
+	switch {
+	case ie.VideoFrame:
+		ie.image, err = LoadVideoFrame(ie.ImagePath, ie.FrameAt)
+	case ie.PDFPage > 0:
+		ie.image, err = LoadPDFPage(ie.ImagePath, ie.PDFPage, ie.PDFDPI)
+	case ie.GIFFrame > 0 || ie.GIFTime > 0:
+		ie.image, err = LoadGIFFrame(ie.ImagePath, ie.GIFFrame, ie.GIFTime)
+	case isSVG(ie.ImagePath):
+		ie.svg, ie.image, err = loadSVG(ie.ImagePath)
+	default:
+		ie.image, err = LoadImage(ie.ImagePath)
+	}
+	return err
+}
+
+// loadJSON 关联所属合成器，使用其字体设置
+func (te *TextElement) loadJSON(ic *ImageCombiner) error {
+	te.combiner = ic
+	return nil
+}
+
+var colorType = reflect.TypeOf((*color.Color)(nil)).Elem()
+
+// encodeValue 将值转换为可JSON编码的形式
+// 颜色编码为"#rrggbbaa"，主题颜色编码为"@名称"，结构体只保留导出字段（json:"-"除外）
+func encodeValue(v reflect.Value) any {
+	if v.Type() == colorType {
+		if v.IsNil() {
+			return nil
+		}
+		if name, ok := v.Interface().(ThemeColor); ok {
+			return "@" + string(name)
+		}
+		return encodeColor(v.Interface().(color.Color))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		fields := make(map[string]any)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			fields[field.Name] = encodeValue(v.Field(i))
+		}
+		return fields
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = encodeValue(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		items := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			items[iter.Key().String()] = encodeValue(iter.Value())
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
+
+// decodeValue 将encodeValue生成的JSON解码到v，v须可寻址
+func decodeValue(data json.RawMessage, v reflect.Value) error {
+	if v.Type() == colorType {
+		var s *string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == nil {
+			v.Set(reflect.Zero(colorType))
+			return nil
+		}
+		if name, ok := strings.CutPrefix(*s, "@"); ok {
+			v.Set(reflect.ValueOf(ThemeColor(name)))
+			return nil
+		}
+		c, err := ParseHexColor(*s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(c))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if string(data) == "null" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(data, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			raw, ok := fields[field.Name]
+			if !ok || !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			if err := decodeValue(raw, v.Field(i)); err != nil {
+				return fmt.Errorf("%s: %v", field.Name, err)
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 || string(data) == "null" {
+			break
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			if err := decodeValue(items[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || string(data) == "null" {
+			break
+		}
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(items)))
+		for key, raw := range items {
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(raw, item); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), item)
+		}
+		return nil
+	}
+	return json.Unmarshal(data, v.Addr().Interface())
+}
+
+// encodeColor 将颜色编码为"#rrggbbaa"（非预乘透明度）
+func encodeColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// ParseHexColor 解析"#rrggbb"或"#rrggbbaa"格式的颜色
+func ParseHexColor(s string) (color.Color, error) {
+	var n color.NRGBA
+	switch len(s) {
+	case 7:
+		n.A = 255
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &n.R, &n.G, &n.B); err != nil {
+			return nil, fmt.Errorf("invalid color %q", s)
+		}
+	case 9:
+		if _, err := fmt.Sscanf(s, "#%02x%02x%02x%02x", &n.R, &n.G, &n.B, &n.A); err != nil {
+			return nil, fmt.Errorf("invalid color %q", s)
+		}
+	default:
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,91 @@
+package imgcombine
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+// badgeElement 测试用的自定义元素
+type badgeElement struct {
+	X, Y  int
+	Color color.Color
+}
+
+func (b *badgeElement) Draw(g *gg.Context, canvasWidth int) {
+	g.SetColor(b.Color)
+	g.DrawCircle(float64(b.X), float64(b.Y), 10)
+	g.Fill()
+}
+
+// TestJSONRoundTrip 测试合成器序列化后可还原并渲染出相同的图片
+func TestJSONRoundTrip(t *testing.T) {
+	RegisterElementType("test_badge", func() CombineElement { return &badgeElement{} })
+
+	imagePath := filepath.Join(t.TempDir(), "logo.png")
+	f, err := os.Create(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, newImage(color.RGBA{0, 128, 255, 255}))
+	f.Close()
+
+	combiner := NewImageCombiner(300, 200)
+	combiner.OutputFormat = PNG
+	combiner.SetQuality(80)
+	combiner.SetSeed(42)
+	combiner.FontPaths = []string{"../Alibaba-PuHuiTi-Medium.ttf"}
+	combiner.AddRectangleElement(0, 0, 300, 200).Color = color.NRGBA{240, 240, 240, 128}
+	if _, err := combiner.AddImageElement(imagePath, 200, 20, Origin); err != nil {
+		t.Fatal(err)
+	}
+	text := combiner.AddTextElement("", 20, 10, 40)
+	text.Spans = []TextSpan{{Text: "原价 "}, {Text: "￥1290", Color: color.RGBA{150, 150, 150, 255}, StrikeThrough: true}}
+	gauge := combiner.AddGaugeElement(70, 0, 100, 10, 60, 50)
+	gauge.Zones = []GaugeZone{{From: 0, To: 50, Color: color.RGBA{255, 0, 0, 255}}, {From: 50, To: 100, Color: color.RGBA{0, 200, 0, 255}}}
+	combiner.AddTimelineElement([]TimelineStep{{Label: "下单", State: StepCompleted}, {Label: "发货", State: StepCurrent}}, 150, 150, 100)
+	combiner.AddConfettiElement(ParticleStar, 20, 0, 0, 300, 200)
+	combiner.AddElement(&badgeElement{X: 280, Y: 180, Color: color.RGBA{255, 0, 255, 255}})
+
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+
+	var restored ImageCombiner
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("反序列化失败: %v", err)
+	}
+	again, err := json.Marshal(&restored)
+	if err != nil {
+		t.Fatalf("再次序列化失败: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("序列化结果不一致:\n%s\n%s", data, again)
+	}
+	if restored.OutputFormat != PNG || restored.quality != 0.8 || !restored.seeded {
+		t.Error("合成器设置未还原")
+	}
+
+	want, _ := combiner.Combine()
+	got, _ := restored.Combine()
+	if !bytes.Equal(want.(*image.RGBA).Pix, got.(*image.RGBA).Pix) {
+		t.Error("还原后的渲染结果不一致")
+	}
+
+	// 未注册的元素类型无法序列化
+	combiner.AddElement(&struct{ badgeElement }{})
+	if _, err := json.Marshal(combiner); err == nil {
+		t.Error("未注册的元素类型应返回错误")
+	}
+	if err := json.Unmarshal([]byte(`{"width":1,"height":1,"elements":[{"type":"missing","props":{}}]}`), &restored); err == nil {
+		t.Error("未知元素类型应返回错误")
+	}
+}
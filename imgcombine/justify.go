@@ -0,0 +1,55 @@
+package imgcombine
+
+import (
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+// justified 判断一行是否需要两端对齐，段落的最后一行和按制表位排列的行保持左对齐
+func (te *TextElement) justified(line string, paragraphEnd bool) bool {
+	return te.Alignment == AlignJustify && te.MaxLineWidth > 0 && !paragraphEnd && !strings.Contains(line, "\t")
+}
+
+// drawJustified 两端对齐绘制一行文本，返回绘制宽度
+// 含空格的行拉伸空格宽度，否则均匀拉伸相邻字符的间距
+func (te *TextElement) drawJustified(g *gg.Context, line string, x, y float64, style synthStyle) float64 {
+	line = strings.TrimRight(line, " ")
+	natural, _ := g.MeasureString(line)
+	maxWidth := float64(te.MaxLineWidth)
+
+	var parts []string
+	sep := ""
+	if strings.Contains(line, " ") {
+		parts = strings.Split(line, " ")
+		sep = " "
+	} else {
+		for _, r := range line {
+			parts = append(parts, string(r))
+		}
+	}
+	if len(parts) < 2 || natural >= maxWidth {
+		style.drawString(g, line, x, y, 0, 0)
+		return natural
+	}
+
+	// 各部分单独测量后的宽度之和与整行测量略有差异（字距调整），以实际宽度计算额外间距
+	partsWidth := 0.0
+	for _, part := range parts {
+		w, _ := g.MeasureString(part)
+		partsWidth += w
+	}
+	sepWidth, _ := g.MeasureString(sep)
+	gap := (maxWidth - partsWidth) / float64(len(parts)-1)
+	if sep != "" {
+		gap = max(gap, sepWidth)
+	}
+
+	cursor := x
+	for _, part := range parts {
+		style.drawString(g, part, cursor, y, 0, 0)
+		w, _ := g.MeasureString(part)
+		cursor += w + gap
+	}
+	return maxWidth
+}
@@ -0,0 +1,217 @@
+package imgcombine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/fogleman/gg"
+)
+
+// CacheableElement 可缓存渲染结果的元素，绘制结果仅取决于元素的导出属性和画布尺寸
+// 合成器设置了LayerCache时，相同属性的元素直接复用已渲染的图层
+type CacheableElement interface {
+	CombineElement
+	Cacheable() bool
+}
+
+// layerSource 绘制结果还取决于未导出数据（如已加载的图片）的可缓存元素，将该数据写入缓存键
+type layerSource interface {
+	writeLayerSource(w io.Writer)
+}
+
+// LayerCache 元素图层缓存，按元素属性哈希保存渲染结果，可在多个合成器间共享
+// 默认不限大小，长期运行的服务应通过SetMaxBytes设置上限，超出时淘汰最久未使用的图层
+type LayerCache struct {
+	mu     sync.Mutex
+	layers *lruCache[string, *cachedLayer]
+	hits   int
+	misses int
+}
+
+// cachedLayer 裁剪到非透明区域的元素图层
+type cachedLayer struct {
+	image  *image.RGBA
+	offset image.Point
+}
+
+// NewLayerCache 创建图层缓存
+func NewLayerCache() *LayerCache {
+	return &LayerCache{layers: newLRUCache[string, *cachedLayer](0)}
+}
+
+// SetMaxBytes 设置缓存图层像素数据的总字节数上限，0表示不限制
+func (c *LayerCache) SetMaxBytes(n int) {
+	c.layers.setLimit(n)
+}
+
+// Usage 返回缓存的图层数量和像素数据总字节数
+func (c *LayerCache) Usage() (layers, bytes int) {
+	return c.layers.usage()
+}
+
+// Stats 返回缓存命中与未命中次数
+func (c *LayerCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Clear 清空缓存
+func (c *LayerCache) Clear() {
+	c.layers.clear()
+}
+
+// draw 绘制元素，命中缓存时直接复用图层，否则渲染到透明图层并缓存
+func (c *LayerCache) draw(g *gg.Context, element CombineElement, canvasWidth int) {
+	key, err := layerKey(element, g.Width(), g.Height())
+	if err != nil {
+		element.Draw(g, canvasWidth)
+		return
+	}
+
+	layer, ok := c.layers.get(key)
+	c.mu.Lock()
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		scratch := getLayer(g.Width(), g.Height())
+		element.Draw(gg.NewContextForRGBA(scratch), canvasWidth)
+		layer = cropLayer(scratch)
+		putLayer(scratch)
+		c.layers.add(key, layer, layer.size())
+	}
+
+	if layer.image != nil {
+		g.DrawImage(layer.image, layer.offset.X, layer.offset.Y)
+	}
+}
+
+// size 返回图层像素数据的字节数，透明图层按1计算
+func (l *cachedLayer) size() int {
+	if l.image == nil {
+		return 1
+	}
+	return len(l.image.Pix)
+}
+
+// layerKey 由元素类型、导出属性和画布尺寸计算缓存键
+func layerKey(element CombineElement, width, height int) (string, error) {
+	props, err := json.Marshal(encodeValue(reflect.ValueOf(element)))
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%T:%dx%d:", element, width, height)
+	h.Write(props)
+	if s, ok := element.(layerSource); ok {
+		s.writeLayerSource(h)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cropLayer 将图层裁剪到非透明像素的外接矩形，完全透明时image为nil
+func cropLayer(img *image.RGBA) *cachedLayer {
+	var box image.Rectangle
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if img.Pix[img.PixOffset(x, y)+3] > 0 {
+				box = box.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if box.Empty() {
+		return &cachedLayer{}
+	}
+
+	cropped := image.NewRGBA(image.Rect(0, 0, box.Dx(), box.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, box.Min, draw.Src)
+	return &cachedLayer{image: cropped, offset: box.Min}
+}
+
+// Cacheable 实现CacheableElement接口
+func (he *HeatmapElement) Cacheable() bool { return true }
+
+// Cacheable 实现CacheableElement接口
+func (ge *GaugeElement) Cacheable() bool { return true }
+
+// Cacheable 实现CacheableElement接口
+func (te *TimelineElement) Cacheable() bool { return true }
+
+// Cacheable 实现CacheableElement接口
+func (de *DonutElement) Cacheable() bool { return true }
+
+// Cacheable 实现CacheableElement接口，图片按像素内容参与缓存键，
+// 图片类型无法读取像素数据或以元素作遮罩时不缓存
+func (ie *ImageElement) Cacheable() bool {
+	if ie.Mask != nil && (ie.Mask.Element != nil || !writePixels(io.Discard, ie.Mask.image)) {
+		return false
+	}
+	return writePixels(io.Discard, ie.uncropped())
+}
+
+// writeLayerSource 写入源图片、SVG和遮罩图片的内容，同一路径的文件变化或内存图片不同时缓存键随之不同
+func (ie *ImageElement) writeLayerSource(w io.Writer) {
+	writePixels(w, ie.uncropped())
+	w.Write(ie.svg)
+	if ie.Mask != nil {
+		writePixels(w, ie.Mask.image)
+	}
+}
+
+// writePixels 写入图片的类型、尺寸和像素数据，不支持的图片类型返回false
+func writePixels(w io.Writer, img image.Image) bool {
+	switch m := img.(type) {
+	case nil:
+		fmt.Fprint(w, "nil;")
+	case *image.RGBA:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.NRGBA:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.RGBA64:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.NRGBA64:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.Gray:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.Gray16:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.Alpha:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.CMYK:
+		fmt.Fprintf(w, "%T%v%d;", m, m.Rect, m.Stride)
+		w.Write(m.Pix)
+	case *image.Paletted:
+		fmt.Fprintf(w, "%T%v%d%v;", m, m.Rect, m.Stride, m.Palette)
+		w.Write(m.Pix)
+	case *image.YCbCr:
+		fmt.Fprintf(w, "%T%v%d%d%v;", m, m.Rect, m.YStride, m.CStride, m.SubsampleRatio)
+		w.Write(m.Y)
+		w.Write(m.Cb)
+		w.Write(m.Cr)
+	case *image.Uniform:
+		fmt.Fprintf(w, "%T%v;", m, m.C)
+	default:
+		return false
+	}
+	return true
+}
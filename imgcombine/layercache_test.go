@@ -0,0 +1,125 @@
+package imgcombine
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// TestLayerCache 测试相同属性的元素复用缓存图层，渲染结果与直接绘制一致
+func TestLayerCache(t *testing.T) {
+	cache := NewLayerCache()
+	build := func(value float64) *ImageCombiner {
+		combiner := NewImageCombiner(300, 200)
+		combiner.LayerCache = cache
+		combiner.AddRectangleElement(0, 0, 300, 200).Color = color.RGBA{30, 30, 30, 255}
+		combiner.AddHeatmapElement([][]float64{{0, 1}, {1, 0}}, 10, 10, 100, 100)
+		gauge := combiner.AddGaugeElement(value, 0, 100, 150, 10, 60)
+		gauge.TrackColor = color.NRGBA{200, 200, 200, 128}
+		return combiner
+	}
+
+	first, _ := build(40).Combine()
+	second, _ := build(40).Combine()
+	if hits, misses := cache.Stats(); hits != 2 || misses != 2 {
+		t.Errorf("缓存统计错误: 命中 %d，未命中 %d", hits, misses)
+	}
+	if !bytes.Equal(first.(*image.RGBA).Pix, second.(*image.RGBA).Pix) {
+		t.Error("命中缓存时渲染结果应一致")
+	}
+
+	// 与不使用缓存的渲染结果一致，半透明边缘经过两次合成允许少量舍入误差
+	direct := build(40)
+	direct.LayerCache = nil
+	uncached, _ := direct.Combine()
+	diff := 0
+	for i, v := range uncached.(*image.RGBA).Pix {
+		if abs(int(v)-int(first.(*image.RGBA).Pix[i])) > 3 {
+			diff++
+		}
+	}
+	if diff > 0 {
+		t.Errorf("缓存图层与直接绘制存在 %d 处差异", diff)
+	}
+
+	// 属性变化时重新渲染
+	build(80).Combine()
+	if hits, misses := cache.Stats(); hits != 3 || misses != 3 {
+		t.Errorf("属性变化后缓存统计错误: 命中 %d，未命中 %d", hits, misses)
+	}
+}
+
+// TestLayerCacheMaxBytes 测试设置上限后淘汰最久未使用的图层
+func TestLayerCacheMaxBytes(t *testing.T) {
+	cache := NewLayerCache()
+	render := func(value float64) {
+		combiner := NewImageCombiner(200, 200)
+		combiner.LayerCache = cache
+		combiner.AddGaugeElement(value, 0, 100, 100, 10, 60)
+		combiner.Combine()
+	}
+
+	render(10)
+	_, size := cache.Usage()
+	cache.SetMaxBytes(size * 2)
+	for _, value := range []float64{20, 30, 10} {
+		render(value)
+	}
+	if layers, bytes := cache.Usage(); layers != 2 || bytes > size*2 {
+		t.Fatalf("缓存应保留2个图层且不超过上限，实际 %d 个 %d 字节", layers, bytes)
+	}
+
+	// 最近使用的10仍在缓存中，最早淘汰的是20
+	hits, _ := cache.Stats()
+	render(10)
+	if h, _ := cache.Stats(); h != hits+1 {
+		t.Error("最近使用的图层不应被淘汰")
+	}
+	render(20)
+	if h, _ := cache.Stats(); h != hits+1 {
+		t.Error("最久未使用的图层应已被淘汰")
+	}
+}
+
+// TestLayerCacheImage 测试模糊背景等图片元素按图片内容缓存
+func TestLayerCacheImage(t *testing.T) {
+	cache := NewLayerCache()
+	render := func(img image.Image) image.Image {
+		combiner := NewImageCombiner(40, 40)
+		combiner.LayerCache = cache
+		background := combiner.AddImageElementFromImage(img, 0, 0, WidthHeight)
+		background.Width, background.Height, background.Blur = 40, 40, 4
+		out, _ := combiner.Combine()
+		return out
+	}
+
+	red, blue := solidImage(10, 10, color.RGBA{255, 0, 0, 255}), solidImage(10, 10, color.RGBA{0, 0, 255, 255})
+	render(red)
+	render(solidImage(10, 10, color.RGBA{255, 0, 0, 255}))
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("相同内容的图片应命中缓存: 命中 %d，未命中 %d", hits, misses)
+	}
+	// 属性相同但图片不同时不能复用
+	if c := rgb(render(blue).At(20, 20)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("图片不同时应重新渲染，实际 %v", c)
+	}
+	if _, misses := cache.Stats(); misses != 2 {
+		t.Errorf("图片不同时应未命中缓存，未命中 %d 次", misses)
+	}
+
+	// 无法读取像素数据的图片类型和元素遮罩不缓存
+	if (&ImageElement{image: gradientImage{}}).Cacheable() {
+		t.Error("不支持的图片类型不应缓存")
+	}
+	if (&ImageElement{image: red, Mask: &Mask{Element: &RectangleElement{}}}).Cacheable() {
+		t.Error("以元素作遮罩的图片不应缓存")
+	}
+}
+
+// gradientImage 按坐标计算颜色、没有像素数据的图片
+type gradientImage struct{}
+
+func (gradientImage) ColorModel() color.Model { return color.RGBAModel }
+func (gradientImage) Bounds() image.Rectangle { return image.Rect(0, 0, 10, 10) }
+func (gradientImage) At(x, y int) color.Color { return color.RGBA{uint8(x * 25), 0, 0, 255} }
@@ -0,0 +1,13 @@
+//go:build !js
+
+package imgcombine
+
+import (
+	"io"
+	"os"
+)
+
+// openLocal 打开本地图片文件
+func openLocal(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
@@ -0,0 +1,33 @@
+//go:build js
+
+package imgcombine
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"syscall/js"
+)
+
+// openLocal 浏览器中没有本地文件系统，非http(s)路径按相对于当前页面的地址通过fetch加载
+func openLocal(path string) (io.ReadCloser, error) {
+	base, err := url.Parse(js.Global().Get("location").Get("href").String())
+	if err != nil {
+		return nil, err
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(base.ResolveReference(ref).String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetch %s: %s", path, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,91 @@
+package imgcombine
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruCache 并发安全的LRU缓存，总开销超过上限时淘汰最久未使用的条目
+// 开销由调用方给出（条目数或字节数），上限为0表示不限制
+type lruCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	limit   int
+	cost    int
+	order   *list.List // 队首为最近使用
+	entries map[K]*list.Element
+}
+
+// lruEntry 缓存条目
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+	cost  int
+}
+
+func newLRUCache[K comparable, V any](limit int) *lruCache[K, V] {
+	return &lruCache[K, V]{limit: limit, order: list.New(), entries: make(map[K]*list.Element)}
+}
+
+// get 查找条目并标记为最近使用
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// add 添加或替换条目，随后按上限淘汰
+// 单个条目开销超过上限时不缓存，避免清空其余条目
+func (c *lruCache[K, V]) add(key K, value V, cost int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	if c.limit > 0 && cost > c.limit {
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, cost: cost})
+	c.cost += cost
+	c.evict()
+}
+
+// setLimit 修改上限并立即淘汰超出部分
+func (c *lruCache[K, V]) setLimit(limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limit = limit
+	c.evict()
+}
+
+// usage 返回条目数和总开销
+func (c *lruCache[K, V]) usage() (entries, cost int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries), c.cost
+}
+
+// clear 清空缓存
+func (c *lruCache[K, V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[K]*list.Element)
+	c.cost = 0
+}
+
+func (c *lruCache[K, V]) evict() {
+	for c.limit > 0 && c.cost > c.limit {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lruCache[K, V]) remove(e *list.Element) {
+	entry := c.order.Remove(e).(*lruEntry[K, V])
+	delete(c.entries, entry.key)
+	c.cost -= entry.cost
+}
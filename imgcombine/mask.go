@@ -0,0 +1,128 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/fogleman/gg"
+)
+
+// MaskShape 内置遮罩形状枚举
+type MaskShape string
+
+const (
+	MaskCircle  MaskShape = "circle"  // 椭圆，宽高相等时为圆形
+	MaskHeart   MaskShape = "heart"   // 心形
+	MaskHexagon MaskShape = "hexagon" // 尖顶六边形
+	MaskStar    MaskShape = "star"    // 五角星
+)
+
+// Mask 图片遮罩，以遮罩的透明度裁剪图片，可用于心形、六边形等异形头像
+// 遮罩拉伸到图片缩放后的尺寸，与RoundCorner同时设置时两者取交集
+type Mask struct {
+	Shape     MaskShape      // 内置形状
+	ImagePath string         // 遮罩图片路径，使用其透明度通道，优先于Shape
+	Element   CombineElement `json:"-"` // 遮罩元素，坐标相对于图片左上角，优先于ImagePath
+	image     image.Image    // 缓存的遮罩图片
+}
+
+// NewShapeMask 创建内置形状遮罩
+func NewShapeMask(shape MaskShape) *Mask {
+	return &Mask{Shape: shape}
+}
+
+// NewImageMask 以图片的透明度通道创建遮罩
+func NewImageMask(path string) (*Mask, error) {
+	m := &Mask{ImagePath: path}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewElementMask 以元素绘制结果的透明度创建遮罩，元素坐标相对于图片左上角
+func NewElementMask(element CombineElement) *Mask {
+	return &Mask{Element: element}
+}
+
+// load 加载遮罩图片
+func (m *Mask) load() error {
+	if m.ImagePath == "" || m.image != nil {
+		return nil
+	}
+	img, err := LoadImage(m.ImagePath)
+	if err != nil {
+		return err
+	}
+	m.image = img
+	return nil
+}
+
+// layer 返回尺寸为width x height的遮罩图层，仅透明度有效
+func (m *Mask) layer(width, height int) image.Image {
+	switch {
+	case m.Element != nil:
+		g := gg.NewContext(width, height)
+		m.Element.Draw(g, width)
+		return g.Image()
+	case m.image != nil:
+		return CurrentAccelerator().Resize(m.image, width, height)
+	}
+
+	g := gg.NewContext(width, height)
+	w, h := float64(width), float64(height)
+	switch m.Shape {
+	case MaskHeart:
+		g.MoveTo(0.5*w, 0.3*h)
+		g.CubicTo(0.5*w, 0.27*h, 0.45*w, 0.15*h, 0.25*w, 0.15*h)
+		g.CubicTo(0, 0.15*h, 0, 0.4*h, 0, 0.4*h)
+		g.CubicTo(0, 0.55*h, 0.1*w, 0.77*h, 0.5*w, 0.95*h)
+		g.CubicTo(0.9*w, 0.77*h, w, 0.55*h, w, 0.4*h)
+		g.CubicTo(w, 0.4*h, w, 0.15*h, 0.75*w, 0.15*h)
+		g.CubicTo(0.6*w, 0.15*h, 0.5*w, 0.27*h, 0.5*w, 0.3*h)
+		g.ClosePath()
+	case MaskHexagon:
+		g.MoveTo(0.5*w, 0)
+		g.LineTo(w, 0.25*h)
+		g.LineTo(w, 0.75*h)
+		g.LineTo(0.5*w, h)
+		g.LineTo(0, 0.75*h)
+		g.LineTo(0, 0.25*h)
+		g.ClosePath()
+	case MaskStar:
+		// 按短边确定外接圆，星形在图片中居中
+		r := min(w, h) / 2
+		g.Translate(w/2, h/2)
+		g.Scale(w/2/r, h/2/r)
+		drawStar(g, 0, 0, r, 0)
+	default:
+		g.DrawEllipse(w/2, h/2, w/2, h/2)
+	}
+	g.SetColor(color.White)
+	g.Fill()
+	return g.Image()
+}
+
+// apply 返回按遮罩透明度裁剪后的图片
+func (m *Mask) apply(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	layer := m.layer(bounds.Dx(), bounds.Dy())
+	lb := layer.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			_, _, _, a := layer.At(lb.Min.X+x, lb.Min.Y+y).RGBA()
+			if a == 0xffff {
+				continue
+			}
+			i := rgba.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				rgba.Pix[i+c] = uint8(uint32(rgba.Pix[i+c]) * a / 0xffff)
+			}
+		}
+	}
+	return rgba
+}
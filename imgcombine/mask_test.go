@@ -0,0 +1,89 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestImageMask 测试内置形状、元素和图片遮罩
+func TestImageMask(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	render := func(mask *Mask) image.Image {
+		combiner := NewImageCombiner(100, 100)
+		combiner.AddElement(&ImageElement{image: solidImage(100, 100, red), ZoomMode: Origin, Alpha: 255, Mask: mask})
+		img, _ := combiner.Combine()
+		return img
+	}
+
+	for _, shape := range []MaskShape{MaskCircle, MaskHeart, MaskHexagon, MaskStar} {
+		img := render(NewShapeMask(shape))
+		if c := rgb(img.At(50, 55)); c != [3]uint32{255, 0, 0} {
+			t.Errorf("%s遮罩中心应保留图片，实际 %v", shape, c)
+		}
+		if c := rgb(img.At(2, 2)); c != [3]uint32{255, 255, 255} {
+			t.Errorf("%s遮罩角落应透明，实际 %v", shape, c)
+		}
+	}
+
+	// 心形顶部中间的凹口透明
+	if c := rgb(render(NewShapeMask(MaskHeart)).At(50, 20)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("心形顶部凹口应透明，实际 %v", c)
+	}
+
+	img := render(NewElementMask(&RectangleElement{Width: 50, Height: 100, Color: color.Black}))
+	if c := rgb(img.At(25, 50)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("元素遮罩覆盖处应保留图片，实际 %v", c)
+	}
+	if c := rgb(img.At(75, 50)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("元素遮罩以外应透明，实际 %v", c)
+	}
+
+	// 遮罩图片拉伸到图片尺寸，下半部分半透明
+	maskImg := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			a := uint8(255)
+			if y >= 5 {
+				a = 0
+			}
+			maskImg.Set(x, y, color.NRGBA{A: a})
+		}
+	}
+	path := filepath.Join(t.TempDir(), "mask.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, maskImg)
+	f.Close()
+
+	mask, err := NewImageMask(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img = render(mask)
+	if c := rgb(img.At(50, 20)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("图片遮罩不透明处应保留图片，实际 %v", c)
+	}
+	if c := rgb(img.At(50, 80)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("图片遮罩透明处应透明，实际 %v", c)
+	}
+
+	data, err := json.Marshal(encodeValue(reflect.ValueOf(&ImageElement{Mask: mask})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored ImageElement
+	if err := decodeValue(data, reflect.ValueOf(&restored).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Mask == nil || restored.Mask.ImagePath != path {
+		t.Errorf("遮罩未正确还原: %+v", restored.Mask)
+	}
+}
@@ -0,0 +1,168 @@
+package imgcombine
+
+import (
+	"math"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// TextMetrics 文本排版测量结果，坐标为未旋转时的画布坐标
+type TextMetrics struct {
+	Lines      []string  // 换行后的各行文本，竖排时为各列
+	LineWidths []float64 // 各行宽度，竖排时为各列宽度
+	LineHeight float64   // 行高，竖排时为列间距
+	Width      float64   // 最长行宽度，竖排时为全部列占用的宽度
+	Height     float64   // 从首行顶部到末行底部的高度
+	Left, Top  float64   // 外接矩形左上角坐标
+}
+
+// Bottom 返回外接矩形底边的Y坐标，用于将后续元素放在文本下方
+func (m TextMetrics) Bottom() float64 {
+	return m.Top + m.Height
+}
+
+// GetHeight 计算文本元素换行后的高度
+func (te *TextElement) GetHeight() float64 {
+	return te.Measure().Height
+}
+
+// lineHeight 返回行高：优先使用自定义行高，未设置时按主题的行高倍数，默认1.5倍字体大小（富文本取最大字号）
+func (te *TextElement) lineHeight() float64 {
+	if te.LineHeight > 0 {
+		return te.LineHeight
+	}
+	maxSize := te.FontSize
+	for i := range te.Spans {
+		maxSize = max(maxSize, te.spanSize(i))
+	}
+	if t := te.theme(); t != nil && t.LineHeight > 0 {
+		return maxSize * t.LineHeight
+	}
+	return maxSize * 1.5
+}
+
+// Measure 按与Draw相同的排版逻辑测量文本，返回各行内容、宽度和外接矩形
+func (te *TextElement) Measure() TextMetrics {
+	m := te.measure()
+	dx, dy := te.anchorOffset(m)
+	m.Left += dx
+	m.Top += dy
+	return m
+}
+
+// measure 测量未按锚点平移时的文本
+func (te *TextElement) measure() TextMetrics {
+	switch {
+	case len(te.Spans) > 0:
+		return te.measureSpans()
+	case te.Vertical:
+		return te.measureVertical()
+	}
+
+	g := gg.NewContext(1, 1)
+	face, _ := te.face()
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+	g.SetFontFace(face)
+
+	lines, ends := te.wrapParagraphs(g)
+	m := TextMetrics{Lines: lines, LineHeight: te.lineHeight(), Left: float64(te.X)}
+	for i, line := range m.Lines {
+		width := te.lineWidth(g, line)
+		if te.justified(line, ends[i]) && width < float64(te.MaxLineWidth) && len([]rune(strings.TrimRight(line, " "))) > 1 {
+			width = float64(te.MaxLineWidth)
+		}
+		m.LineWidths = append(m.LineWidths, width)
+		m.Width = max(m.Width, width)
+		if i == 0 || te.lineX(width) < m.Left {
+			m.Left = te.lineX(width)
+		}
+	}
+	ascent, descent := faceExtents(face)
+	m.Top = float64(te.Y) - ascent
+	m.Height = float64(len(m.Lines)-1)*m.LineHeight + ascent + descent
+	return m
+}
+
+// measureSpans 测量富文本
+func (te *TextElement) measureSpans() TextMetrics {
+	m := TextMetrics{LineHeight: te.lineHeight(), Left: float64(te.X)}
+	lines := te.layoutSpans()
+	for i, line := range lines {
+		text := ""
+		for _, run := range line.runs {
+			text += string(run.text)
+		}
+		m.Lines = append(m.Lines, text)
+		m.LineWidths = append(m.LineWidths, line.width)
+		m.Width = max(m.Width, line.width)
+		if i == 0 || te.lineX(line.width) < m.Left {
+			m.Left = te.lineX(line.width)
+		}
+	}
+
+	faces, _ := te.spanFaces()
+	ascent, descent := spanExtents(faces)
+	m.Top = float64(te.Y) - ascent
+	m.Height = float64(max(len(lines), 1)-1)*m.LineHeight + ascent + descent
+	return m
+}
+
+// measureVertical 测量竖排文本，每个字符占一个字号的高度
+func (te *TextElement) measureVertical() TextMetrics {
+	columns := te.verticalColumns()
+	m := TextMetrics{LineHeight: te.columnSpacing(), Width: te.verticalWidth(), Top: float64(te.Y)}
+	m.Left = float64(te.X) + te.FontSize - m.Width
+	longest := 0
+	for _, column := range columns {
+		m.Lines = append(m.Lines, string(column))
+		m.LineWidths = append(m.LineWidths, te.FontSize)
+		longest = max(longest, len(column))
+	}
+	m.Height = float64(longest) * te.FontSize
+	return m
+}
+
+// maxLines 返回MaxLineCount与MaxHeight中较严格的行数限制，0表示不限制
+// 按Measure的高度计算：n行高度为(n-1)倍行高加首行上升高度和末行下降高度，至少保留一行
+func (te *TextElement) maxLines(ascent, descent float64) int {
+	limit := te.MaxLineCount
+	if te.MaxHeight > 0 {
+		fit := 1 + int(math.Floor((float64(te.MaxHeight)-ascent-descent)/te.lineHeight()))
+		if fit = max(fit, 1); limit <= 0 || fit < limit {
+			limit = fit
+		}
+	}
+	return limit
+}
+
+// plainExtents 返回普通文本字体的上升高度和下降高度，仅在设置了MaxHeight时创建字体
+func (te *TextElement) plainExtents() (ascent, descent float64) {
+	if te.MaxHeight <= 0 {
+		return 0, 0
+	}
+	face, _ := te.face()
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+	return faceExtents(face)
+}
+
+// spanExtents 返回各片段字体中最大的上升高度和下降高度
+func spanExtents(faces []font.Face) (ascent, descent float64) {
+	for _, face := range faces {
+		a, d := faceExtents(face)
+		ascent, descent = max(ascent, a), max(descent, d)
+	}
+	return ascent, descent
+}
+
+// faceExtents 返回字体的上升高度和下降高度(像素)
+func faceExtents(face font.Face) (ascent, descent float64) {
+	metrics := face.Metrics()
+	return float64(metrics.Ascent) / 64, float64(metrics.Descent) / 64
+}
@@ -0,0 +1,110 @@
+// Package mobile 提供适合gomobile绑定的图片合成接口
+// 仅使用gomobile支持的类型（数值、字符串、[]byte、error），颜色使用"#rrggbb"或"#rrggbbaa"格式，
+// 输入输出均为字节切片，Android/iOS应用可离线渲染与服务端相同的JSON模板
+package mobile
+
+import (
+	"encoding/json"
+
+	"github.com/luckxgo/imgcombine/imgcombine"
+)
+
+// RegisterFont 以字体数据注册字体，模板中的文本元素可通过FontFamily引用
+func RegisterFont(name string, data []byte) error {
+	return imgcombine.DefaultFontRegistry.RegisterBytes(name, data)
+}
+
+// Render 按JSON模板（ImageCombiner.MarshalJSON的输出）渲染图片，返回编码后的图片数据
+func Render(template []byte) ([]byte, error) {
+	var combiner imgcombine.ImageCombiner
+	if err := json.Unmarshal(template, &combiner); err != nil {
+		return nil, err
+	}
+	return combiner.ToBytes()
+}
+
+// Combiner 图片合成器
+type Combiner struct {
+	combiner *imgcombine.ImageCombiner
+}
+
+// NewCombiner 创建图片合成器
+func NewCombiner(width, height int) *Combiner {
+	return &Combiner{combiner: imgcombine.NewImageCombiner(width, height)}
+}
+
+// SetOutputFormat 设置输出格式，"jpg"或"png"
+func (c *Combiner) SetOutputFormat(format string) {
+	c.combiner.OutputFormat = imgcombine.OutputFormat(format)
+}
+
+// SetQuality 设置输出图片质量（1-100），仅对JPG格式有效
+func (c *Combiner) SetQuality(quality int) error {
+	return c.combiner.SetQuality(quality)
+}
+
+// AddImage 添加图片元素，path为本地文件路径或http(s)地址，width和height为0时保持原始尺寸
+func (c *Combiner) AddImage(path string, x, y, width, height int) error {
+	element, err := c.combiner.AddImageElement(path, x, y, zoomMode(width, height))
+	if err != nil {
+		return err
+	}
+	element.Width = width
+	element.Height = height
+	return nil
+}
+
+// AddImageBytes 添加内存中的图片数据（PNG、JPEG等），用于相册或相机获取的图片，width和height为0时保持原始尺寸
+// 此类图片没有路径，ToJSON导出的模板中以PNG编码内嵌图片数据
+func (c *Combiner) AddImageBytes(data []byte, x, y, width, height int) error {
+	element, err := c.combiner.AddImageElementFromBytes(data, x, y, zoomMode(width, height))
+	if err != nil {
+		return err
+	}
+	element.Width = width
+	element.Height = height
+	return nil
+}
+
+// AddText 添加文本元素，maxLineWidth大于0时自动换行，fontFamily为RegisterFont注册的名称，可为空
+func (c *Combiner) AddText(text string, fontSize float64, x, y int, hexColor string, maxLineWidth int, fontFamily string) error {
+	textColor, err := imgcombine.ParseHexColor(hexColor)
+	if err != nil {
+		return err
+	}
+	element := c.combiner.AddTextElement(text, fontSize, x, y)
+	element.Color = textColor
+	element.MaxLineWidth = maxLineWidth
+	element.FontFamily = fontFamily
+	return nil
+}
+
+// AddRectangle 添加矩形元素
+func (c *Combiner) AddRectangle(x, y, width, height int, hexColor string, roundCorner int) error {
+	fill, err := imgcombine.ParseHexColor(hexColor)
+	if err != nil {
+		return err
+	}
+	element := c.combiner.AddRectangleElement(x, y, width, height)
+	element.Color = fill
+	element.RoundCorner = roundCorner
+	return nil
+}
+
+// ToBytes 合成并编码图片
+func (c *Combiner) ToBytes() ([]byte, error) {
+	return c.combiner.ToBytes()
+}
+
+// ToJSON 将合成器序列化为JSON模板，可交给Render或服务端渲染
+func (c *Combiner) ToJSON() ([]byte, error) {
+	return json.Marshal(c.combiner)
+}
+
+// zoomMode 宽高均大于0时按指定尺寸缩放，否则保持原始尺寸
+func zoomMode(width, height int) imgcombine.ZoomMode {
+	if width <= 0 || height <= 0 {
+		return imgcombine.Origin
+	}
+	return imgcombine.WidthHeight
+}
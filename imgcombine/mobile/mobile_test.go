@@ -0,0 +1,95 @@
+package mobile
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"testing"
+)
+
+// TestCombiner 测试构建、序列化并按模板渲染
+func TestCombiner(t *testing.T) {
+	fontBytes, err := os.ReadFile("../../Alibaba-PuHuiTi-Medium.ttf")
+	if err != nil {
+		t.Fatalf("读取字体失败: %v", err)
+	}
+	if err := RegisterFont("puhui", fontBytes); err != nil {
+		t.Fatalf("注册字体失败: %v", err)
+	}
+
+	c := NewCombiner(200, 100)
+	c.SetOutputFormat("png")
+	if err := c.AddRectangle(0, 0, 200, 100, "#336699", 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddText("离线渲染", 24, 10, 50, "#ffffffff", 0, "puhui"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddText("x", 24, 10, 50, "white", 0, ""); err == nil {
+		t.Error("无效颜色应返回错误")
+	}
+
+	direct, err := c.ToBytes()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+	template, err := c.ToJSON()
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	rendered, err := Render(template)
+	if err != nil {
+		t.Fatalf("按模板渲染失败: %v", err)
+	}
+	if !bytes.Equal(direct, rendered) {
+		t.Error("按模板渲染的结果应与直接合成一致")
+	}
+	if img, err := png.Decode(bytes.NewReader(rendered)); err != nil || img.Bounds().Dx() != 200 {
+		t.Errorf("输出不是有效的PNG: %v", err)
+	}
+}
+
+// TestAddImageBytes 测试添加内存中的图片数据
+func TestAddImageBytes(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	draw.Draw(photo, photo.Bounds(), image.NewUniform(color.RGBA{0, 128, 0, 255}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	png.Encode(&buf, photo)
+
+	c := NewCombiner(20, 20)
+	c.SetOutputFormat("png")
+	if err := c.AddImageBytes(buf.Bytes(), 0, 0, 20, 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddImageBytes([]byte("garbage"), 0, 0, 0, 0); err == nil {
+		t.Error("无效的图片数据应返回错误")
+	}
+
+	data, err := c.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, g, _, _ := img.At(19, 19).RGBA(); g>>8 != 128 {
+		t.Errorf("图片应缩放到20x20，右下角绿色通道 %d", g>>8)
+	}
+
+	// 导出的模板内嵌图片数据，可直接Render
+	template, err := c.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := Render(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(rendered)); err != nil || !bytes.Equal(rendered, data) {
+		t.Errorf("模板渲染结果应与直接合成一致: %v", err)
+	}
+}
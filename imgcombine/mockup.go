@@ -0,0 +1,178 @@
+package imgcombine
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/fogleman/gg"
+)
+
+// Device 设备样机模板，尺寸为设计尺寸，绘制时按元素宽度等比缩放
+type Device struct {
+	Width, Height int             // 外框尺寸(像素)
+	Screen        image.Rectangle // 屏幕区域，相对外框左上角
+	ScreenRadius  int             // 屏幕圆角半径
+	BodyRadius    int             // 机身圆角半径
+	BodyColor     color.Color     // 机身颜色
+	BodyInset     int             // 机身（笔记本屏幕盖）相对外框两侧的缩进
+	BaseHeight    int             // 底座高度，用于笔记本，底座占满外框宽度
+	FrameImage    string          // 外框图片路径，设置后替代程序绘制的机身，屏幕区域应透明
+}
+
+// 内置设备名称
+const (
+	DevicePhone  = "phone"
+	DeviceTablet = "tablet"
+	DeviceLaptop = "laptop"
+)
+
+var devices = struct {
+	sync.RWMutex
+	byName map[string]Device
+}{byName: map[string]Device{
+	DevicePhone: {
+		Width: 390, Height: 800, Screen: image.Rect(18, 18, 372, 782),
+		ScreenRadius: 36, BodyRadius: 54, BodyColor: color.RGBA{28, 28, 30, 255},
+	},
+	DeviceTablet: {
+		Width: 620, Height: 860, Screen: image.Rect(30, 30, 590, 830),
+		ScreenRadius: 18, BodyRadius: 40, BodyColor: color.RGBA{28, 28, 30, 255},
+	},
+	DeviceLaptop: {
+		Width: 1000, Height: 600, Screen: image.Rect(80, 30, 920, 555),
+		ScreenRadius: 4, BodyRadius: 20, BodyColor: color.RGBA{40, 40, 44, 255},
+		BodyInset: 50, BaseHeight: 25,
+	},
+}}
+
+// RegisterDevice 注册设备样机模板，同名时覆盖内置模板
+func RegisterDevice(name string, device Device) {
+	devices.Lock()
+	defer devices.Unlock()
+	devices.byName[name] = device
+}
+
+// lookupDevice 按名称查找设备模板
+func lookupDevice(name string) (Device, bool) {
+	devices.RLock()
+	defer devices.RUnlock()
+	d, ok := devices.byName[name]
+	return d, ok
+}
+
+// MockupElement 设备样机元素：将截图放入手机、平板或笔记本外框，屏幕按模板裁剪圆角
+type MockupElement struct {
+	Device     string      // 设备模板名称
+	ImagePath  string      // 截图路径
+	X, Y       int         // 外框左上角坐标
+	Width      int         // 外框宽度，高度按模板比例计算
+	Shadow     bool        // 是否绘制投影
+	BodyColor  color.Color // 机身颜色，为nil时使用模板颜色
+	screenshot image.Image
+	frame      image.Image
+}
+
+// AddMockupElement 添加设备样机元素，默认绘制投影
+func (ic *ImageCombiner) AddMockupElement(device, screenshotPath string, x, y, width int) (*MockupElement, error) {
+	element := &MockupElement{Device: device, ImagePath: screenshotPath, X: x, Y: y, Width: width, Shadow: true}
+	if err := element.load(); err != nil {
+		return nil, err
+	}
+
+	ic.AddElement(element)
+	return element, nil
+}
+
+// load 加载截图和外框图片
+func (me *MockupElement) load() error {
+	device, ok := lookupDevice(me.Device)
+	if !ok {
+		return fmt.Errorf("device %q is not registered", me.Device)
+	}
+	img, err := LoadImage(me.ImagePath)
+	if err != nil {
+		return err
+	}
+	me.screenshot = img
+	if device.FrameImage != "" {
+		if me.frame, err = LoadImage(device.FrameImage); err != nil {
+			return fmt.Errorf("load device frame: %v", err)
+		}
+	}
+	return nil
+}
+
+// loadJSON 按ImagePath重新加载截图
+func (me *MockupElement) loadJSON(ic *ImageCombiner) error {
+	return me.load()
+}
+
+// Height 返回按模板比例计算的外框高度
+func (me *MockupElement) Height() int {
+	device, ok := lookupDevice(me.Device)
+	if !ok || device.Width == 0 {
+		return 0
+	}
+	return me.Width * device.Height / device.Width
+}
+
+// Draw 实现CombineElement接口
+func (me *MockupElement) Draw(g *gg.Context, canvasWidth int) {
+	device, ok := lookupDevice(me.Device)
+	if !ok || me.screenshot == nil || device.Width == 0 {
+		return
+	}
+	scale := float64(me.Width) / float64(device.Width)
+	x, y := float64(me.X), float64(me.Y)
+	s := func(v int) float64 { return float64(v) * scale }
+	bodyColor := device.BodyColor
+	if me.BodyColor != nil {
+		bodyColor = me.BodyColor
+	}
+
+	// 机身轮廓：屏幕盖（或整机）加可选的底座
+	outline := func(ctx *gg.Context, dx, dy float64) {
+		lidHeight := device.Height - device.BaseHeight
+		ctx.DrawRoundedRectangle(x+dx+s(device.BodyInset), y+dy, s(device.Width-2*device.BodyInset), s(lidHeight), s(device.BodyRadius))
+		if device.BaseHeight > 0 {
+			ctx.DrawRoundedRectangle(x+dx, y+dy+s(lidHeight), s(device.Width), s(device.BaseHeight), s(device.BaseHeight)/2)
+		}
+	}
+
+	if me.Shadow {
+		scratch := getLayer(g.Width(), g.Height())
+		layer := gg.NewContextForRGBA(scratch)
+		layer.SetColor(color.NRGBA{0, 0, 0, 90})
+		outline(layer, 0, s(16))
+		layer.Fill()
+		g.DrawImage(CurrentAccelerator().Blur(scratch, s(18)), 0, 0)
+		putLayer(scratch)
+	}
+
+	if me.frame == nil {
+		g.Push()
+		g.SetColor(bodyColor)
+		outline(g, 0, 0)
+		g.Fill()
+		g.Pop()
+	}
+
+	screen := &ImageElement{
+		image:       me.screenshot,
+		X:           me.X + int(s(device.Screen.Min.X)),
+		Y:           me.Y + int(s(device.Screen.Min.Y)),
+		Width:       int(s(device.Screen.Dx())),
+		Height:      int(s(device.Screen.Dy())),
+		ZoomMode:    WidthHeight,
+		Alpha:       255,
+		RoundCorner: int(s(device.ScreenRadius)),
+	}
+	screen.Draw(g, canvasWidth)
+
+	if me.frame != nil {
+		frame := &ImageElement{image: me.frame, X: me.X, Y: me.Y, Width: me.Width, Height: me.Height(), ZoomMode: WidthHeight, Alpha: 255}
+		frame.Draw(g, canvasWidth)
+	}
+}
@@ -0,0 +1,57 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMockup 测试截图放入设备外框的屏幕区域
+func TestMockup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "screenshot.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, solidImage(100, 200, color.RGBA{0, 200, 0, 255}))
+	f.Close()
+
+	combiner := NewImageCombiner(300, 500)
+	mockup, err := combiner.AddMockupElement(DevicePhone, path, 50, 50, 195)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mockup.Height() != 400 {
+		t.Errorf("外框高度应按比例为400，实际 %d", mockup.Height())
+	}
+
+	img, _ := combiner.Combine()
+	if c := rgb(img.At(147, 250)); c != [3]uint32{0, 200, 0} {
+		t.Errorf("屏幕中央应为截图，实际 %v", c)
+	}
+	if c := rgb(img.At(53, 250)); c != [3]uint32{28, 28, 30} {
+		t.Errorf("边框应为机身颜色，实际 %v", c)
+	}
+	if c := rgb(img.At(147, 456)); c[0] >= 255 {
+		t.Errorf("机身下方应有投影，实际 %v", c)
+	}
+
+	if _, err := combiner.AddMockupElement("watch", path, 0, 0, 100); err == nil {
+		t.Error("未注册的设备应返回错误")
+	}
+
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if m := restored.elements[0].(*MockupElement); m.screenshot == nil || m.Device != DevicePhone {
+		t.Errorf("样机元素未正确恢复: %+v", m)
+	}
+}
@@ -0,0 +1,114 @@
+package imgcombine
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/fogleman/gg"
+)
+
+// ErrModerationBlocked 图片未通过审核被拦截
+var ErrModerationBlocked = errors.New("image blocked by moderation")
+
+// ModerationAction 审核结果的处理策略枚举
+type ModerationAction string
+
+const (
+	ModerationPass      ModerationAction = "pass"      // 通过，原样输出
+	ModerationBlock     ModerationAction = "block"     // 拦截，不输出图片
+	ModerationWatermark ModerationAction = "watermark" // 输出，但覆盖警示水印
+)
+
+// ModerationResult 审核结果
+type ModerationResult struct {
+	Action    ModerationAction // 处理策略
+	Reason    string           // 原因说明
+	Watermark string           // 水印文本，为空时使用Reason
+}
+
+// Moderator 输出前的审核钩子，接收合成后的图片和全部文本内容
+// 可接入鉴黄、敏感词等审核服务，在图片保存或编码之前决定放行、拦截或加水印
+type Moderator interface {
+	Moderate(img image.Image, texts []string) (ModerationResult, error)
+}
+
+// ModeratorFunc 函数形式的Moderator
+type ModeratorFunc func(img image.Image, texts []string) (ModerationResult, error)
+
+// Moderate 实现Moderator接口
+func (f ModeratorFunc) Moderate(img image.Image, texts []string) (ModerationResult, error) {
+	return f(img, texts)
+}
+
+// render 合成图片并执行审核、颜色模式转换和隐形水印嵌入，供保存与编码输出使用
+func (ic *ImageCombiner) render() (image.Image, error) {
+	img, err := ic.Combine()
+	if err != nil {
+		return nil, err
+	}
+
+	if ic.Moderator != nil {
+		result, err := ic.Moderator.Moderate(img, ic.texts())
+		if err != nil {
+			return nil, fmt.Errorf("moderation: %w", err)
+		}
+
+		switch result.Action {
+		case ModerationBlock:
+			return nil, fmt.Errorf("%w: %s", ErrModerationBlocked, result.Reason)
+		case ModerationWatermark:
+			text := result.Watermark
+			if text == "" {
+				text = result.Reason
+			}
+			img = ic.drawWarningWatermark(img, text)
+		}
+	}
+
+	// 隐形水印在颜色模式转换之后最后嵌入，避免被灰度、黑白等处理覆盖
+	img = applyColorMode(img, ic.ColorMode)
+	if ic.InvisibleWatermark != "" {
+		marked, err := EmbedWatermark(img, ic.InvisibleWatermark)
+		if err != nil {
+			return nil, err
+		}
+		img = marked
+	}
+	return img, nil
+}
+
+// texts 返回所有文本元素（含富文本片段和圆弧文本）的内容
+func (ic *ImageCombiner) texts() []string {
+	var texts []string
+	for _, element := range ic.elements {
+		if text := plainText(element); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// drawWarningWatermark 在图片上平铺倾斜的半透明警示文本
+func (ic *ImageCombiner) drawWarningWatermark(img image.Image, text string) image.Image {
+	g := gg.NewContextForImage(img)
+	w, h := float64(g.Width()), float64(g.Height())
+
+	fontSize := w / 12
+	loadFontFace(g, ic.FontPaths, fontSize)
+	g.SetColor(color.NRGBA{255, 0, 0, 96})
+
+	// 以画布中心旋转，平铺范围取对角线长度以覆盖四角
+	g.RotateAbout(gg.Radians(-30), w/2, h/2)
+	textWidth, _ := g.MeasureString(text)
+	stepX := textWidth + fontSize*2
+	stepY := fontSize * 4
+	diagonal := w + h
+	for y := h/2 - diagonal; y < h/2+diagonal; y += stepY {
+		for x := w/2 - diagonal; x < w/2+diagonal; x += stepX {
+			g.DrawString(text, x, y)
+		}
+	}
+	return g.Image()
+}
@@ -0,0 +1,69 @@
+package imgcombine
+
+import (
+	"errors"
+	"image"
+	"strings"
+	"testing"
+)
+
+// TestModeration 测试审核钩子的放行、拦截与水印策略
+func TestModeration(t *testing.T) {
+	newCombiner := func(text string) *ImageCombiner {
+		combiner := NewImageCombiner(200, 100)
+		combiner.OutputFormat = PNG
+		combiner.AddTextElement(text, 20, 10, 50)
+		combiner.Moderator = ModeratorFunc(func(img image.Image, texts []string) (ModerationResult, error) {
+			for _, text := range texts {
+				if strings.Contains(text, "违禁") {
+					return ModerationResult{Action: ModerationBlock, Reason: "敏感词"}, nil
+				}
+				if strings.Contains(text, "待审") {
+					return ModerationResult{Action: ModerationWatermark, Watermark: "SAMPLE"}, nil
+				}
+			}
+			return ModerationResult{Action: ModerationPass}, nil
+		})
+		return combiner
+	}
+
+	if _, err := newCombiner("正常文本").ToBytes(); err != nil {
+		t.Errorf("正常内容不应被拦截: %v", err)
+	}
+
+	_, err := newCombiner("违禁内容").ToBytes()
+	if !errors.Is(err, ErrModerationBlocked) {
+		t.Errorf("违禁内容应被拦截，实际 %v", err)
+	}
+
+	// 富文本片段和圆弧文本同样提交审核
+	spans := newCombiner("")
+	spans.elements[0].(*TextElement).Spans = []TextSpan{{Text: "违"}, {Text: "禁"}}
+	if _, err := spans.ToBytes(); !errors.Is(err, ErrModerationBlocked) {
+		t.Errorf("富文本中的违禁内容应被拦截，实际 %v", err)
+	}
+	arc := newCombiner("正常文本")
+	arc.AddArcTextElement("违禁内容", 12, 100, 50, 40)
+	if _, err := arc.ToBytes(); !errors.Is(err, ErrModerationBlocked) {
+		t.Errorf("圆弧文本中的违禁内容应被拦截，实际 %v", err)
+	}
+
+	plain, _ := newCombiner("待审").Combine()
+	marked, err := newCombiner("待审").render()
+	if err != nil {
+		t.Fatalf("水印策略不应返回错误: %v", err)
+	}
+	changed := false
+	bounds := plain.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y && !changed; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if rgb(plain.At(x, y)) != rgb(marked.At(x, y)) {
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		t.Error("水印策略应在图片上绘制水印")
+	}
+}
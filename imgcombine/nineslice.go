@@ -0,0 +1,64 @@
+package imgcombine
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Insets 九宫格切片的四边内边距（源图像素）
+type Insets struct {
+	Top, Right, Bottom, Left int
+}
+
+// nineSlice 按九宫格将图片缩放到width x height：四角保持原尺寸，上下边只横向拉伸，
+// 左右边只纵向拉伸，中间区域双向拉伸；目标尺寸小于两侧内边距之和时四角按比例缩小
+func nineSlice(img image.Image, insets Insets, width, height int, filter ResizeFilter) image.Image {
+	bounds := img.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	left, right := clampInsets(insets.Left, insets.Right, sw)
+	top, bottom := clampInsets(insets.Top, insets.Bottom, sh)
+
+	dl, dr := shrinkInsets(left, right, width)
+	dt, db := shrinkInsets(top, bottom, height)
+
+	src := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	srcX := [4]int{0, left, sw - right, sw}
+	srcY := [4]int{0, top, sh - bottom, sh}
+	dstX := [4]int{0, dl, width - dr, width}
+	dstY := [4]int{0, dt, height - db, height}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			s := image.Rect(srcX[col], srcY[row], srcX[col+1], srcY[row+1])
+			d := image.Rect(dstX[col], dstY[row], dstX[col+1], dstY[row+1])
+			if s.Empty() || d.Empty() {
+				continue
+			}
+			// 切片复制为以原点为左上角的图片后缩放
+			piece := image.NewRGBA(image.Rect(0, 0, s.Dx(), s.Dy()))
+			draw.Draw(piece, piece.Bounds(), src, s.Min, draw.Src)
+			draw.Draw(dst, d, resizeWith(piece, d.Dx(), d.Dy(), filter), image.Point{}, draw.Src)
+		}
+	}
+	return dst
+}
+
+// clampInsets 将一对相对的内边距限制在源图尺寸以内
+func clampInsets(a, b, size int) (int, int) {
+	a, b = max(a, 0), max(b, 0)
+	if a+b > size {
+		a = a * size / (a + b)
+		b = size - a
+	}
+	return a, b
+}
+
+// shrinkInsets 目标尺寸小于内边距之和时按比例缩小内边距
+func shrinkInsets(a, b, size int) (int, int) {
+	if a+b <= size {
+		return a, b
+	}
+	return clampInsets(a, b, size)
+}
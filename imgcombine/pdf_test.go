@@ -0,0 +1,65 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestPDFPage 以模拟的pdftoppm测试PDF页面渲染参数与JSON恢复
+func TestPDFPage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟的pdftoppm为shell脚本")
+	}
+	dir := t.TempDir()
+	pagePath := filepath.Join(dir, "page.png")
+	f, err := os.Create(pagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, solidImage(20, 30, color.RGBA{0, 0, 255, 255}))
+	f.Close()
+
+	// 记录参数并输出预先生成的页面图片
+	argsPath := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsPath + "\ncat " + pagePath + "\n"
+	tool := filepath.Join(dir, "pdftoppm")
+	if err := os.WriteFile(tool, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { PdftoppmPath = path }(PdftoppmPath)
+	PdftoppmPath = tool
+
+	if _, err := LoadPDFPage("doc.pdf", 0, 0); err == nil {
+		t.Error("页码为0应返回错误")
+	}
+
+	combiner := NewImageCombiner(40, 40)
+	element, err := combiner.AddPDFPageElement("doc.pdf", 3, 0, 0, 0, Origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, _ := os.ReadFile(argsPath)
+	if want := "-f 3 -l 3 -r 150 -png -singlefile doc.pdf\n"; string(args) != want {
+		t.Errorf("pdftoppm参数错误: %q", args)
+	}
+	if b := element.image.Bounds(); b.Dx() != 20 || b.Dy() != 30 {
+		t.Errorf("页面图片尺寸错误: %v", b)
+	}
+
+	element.PDFDPI = 300
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &ImageCombiner{}); err != nil {
+		t.Fatal(err)
+	}
+	if args, _ := os.ReadFile(argsPath); string(args) != "-f 3 -l 3 -r 300 -png -singlefile doc.pdf\n" {
+		t.Errorf("JSON恢复时应按页码和DPI重新渲染: %q", args)
+	}
+}
@@ -0,0 +1,149 @@
+package imgcombine
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/fogleman/gg"
+)
+
+// Perspective 透视变换，将图片映射到任意四边形，用于把截图以倾斜角度合成到设备样机上
+// 设置Corners时按四角映射，否则按TiltX/TiltY绕图片中心倾斜；设置后忽略Rotate、Frame和Reflection
+type Perspective struct {
+	Corners [4]gg.Point // 目标四角的画布坐标，依次为左上、右上、右下、左下
+	TiltX   float64     // 绕竖直中轴倾斜的角度(度)，正值右侧远离观察者
+	TiltY   float64     // 绕水平中轴倾斜的角度(度)，正值下方远离观察者
+}
+
+// NewPerspective 创建按四角映射的透视变换
+func NewPerspective(topLeft, topRight, bottomRight, bottomLeft gg.Point) *Perspective {
+	return &Perspective{Corners: [4]gg.Point{topLeft, topRight, bottomRight, bottomLeft}}
+}
+
+// corners 返回目标四角，未设置Corners时由倾斜角度计算，(x, y)为图片左上角
+func (p *Perspective) corners(x, y, w, h int) [4]gg.Point {
+	if p.Corners != [4]gg.Point{} {
+		return p.Corners
+	}
+
+	// 以图片中心为原点旋转后按针孔相机投影，焦距取图片长边的2倍
+	cx, cy := float64(x)+float64(w)/2, float64(y)+float64(h)/2
+	focal := 2 * float64(max(w, h))
+	ax, ay := gg.Radians(p.TiltX), gg.Radians(p.TiltY)
+	var out [4]gg.Point
+	for i, c := range [4]gg.Point{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 1}, {X: -1, Y: 1}} {
+		px, py, pz := c.X*float64(w)/2, c.Y*float64(h)/2, 0.0
+		px, pz = px*math.Cos(ax), px*math.Sin(ax)
+		py, pz = py*math.Cos(ay), pz+py*math.Sin(ay)
+		scale := focal / (focal + pz)
+		out[i] = gg.Point{X: cx + px*scale, Y: cy + py*scale}
+	}
+	return out
+}
+
+// draw 将图片透视映射到目标四边形并绘制，逐像素按逆变换双线性采样
+func (p *Perspective) draw(g *gg.Context, img image.Image, x, y int) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dst := p.corners(x, y, w, h)
+	src := [4]gg.Point{{X: 0, Y: 0}, {X: float64(w), Y: 0}, {X: float64(w), Y: float64(h)}, {X: 0, Y: float64(h)}}
+	inverse, ok := homography(dst, src)
+	if !ok || w == 0 || h == 0 {
+		return
+	}
+
+	minX, minY, maxX, maxY := dst[0].X, dst[0].Y, dst[0].X, dst[0].Y
+	for _, c := range dst[1:] {
+		minX, minY = math.Min(minX, c.X), math.Min(minY, c.Y)
+		maxX, maxY = math.Max(maxX, c.X), math.Max(maxY, c.Y)
+	}
+	box := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY))).
+		Intersect(image.Rect(0, 0, g.Width(), g.Height()))
+	if box.Empty() {
+		return
+	}
+
+	source := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(source, source.Bounds(), img, bounds.Min, draw.Src)
+	warped := image.NewRGBA(box)
+	for py := box.Min.Y; py < box.Max.Y; py++ {
+		for px := box.Min.X; px < box.Max.X; px++ {
+			sx, sy, ok := inverse.apply(float64(px)+0.5, float64(py)+0.5)
+			if !ok || sx < 0 || sy < 0 || sx >= float64(w) || sy >= float64(h) {
+				continue
+			}
+			i := warped.PixOffset(px, py)
+			bilinear(source, sx-0.5, sy-0.5, warped.Pix[i:i+4])
+		}
+	}
+	g.DrawImage(warped, 0, 0)
+}
+
+// matrix3 3x3齐次变换矩阵，按行存储
+type matrix3 [9]float64
+
+// apply 对点做齐次变换
+func (m matrix3) apply(x, y float64) (float64, float64, bool) {
+	d := m[6]*x + m[7]*y + m[8]
+	if d == 0 {
+		return 0, 0, false
+	}
+	return (m[0]*x + m[1]*y + m[2]) / d, (m[3]*x + m[4]*y + m[5]) / d, true
+}
+
+// homography 求解将from四点映射到to四点的单应矩阵，四点共线等退化情况返回false
+func homography(from, to [4]gg.Point) (matrix3, bool) {
+	// 8个未知数的线性方程组，h8固定为1
+	var a [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y, u, v := from[i].X, from[i].Y, to[i].X, to[i].Y
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+
+	// 列主元高斯消元
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for r := col + 1; r < 8; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return matrix3{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := 0; r < 8; r++ {
+			if r == col {
+				continue
+			}
+			f := a[r][col] / a[col][col]
+			for c := col; c < 9; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	var m matrix3
+	for i := 0; i < 8; i++ {
+		m[i] = a[i][8] / a[i][i]
+	}
+	m[8] = 1
+	return m, true
+}
+
+// bilinear 在(x, y)处对预乘透明度的图片双线性插值，结果写入out(RGBA四个字节)
+func bilinear(img *image.RGBA, x, y float64, out []uint8) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	for c := 0; c < 4; c++ {
+		at := func(px, py int) float64 {
+			return float64(img.Pix[img.PixOffset(clampInt(px, 0, w-1), clampInt(py, 0, h-1))+c])
+		}
+		top := at(x0, y0)*(1-fx) + at(x0+1, y0)*fx
+		bottom := at(x0, y0+1)*(1-fx) + at(x0+1, y0+1)*fx
+		out[c] = uint8(math.Round(top*(1-fy) + bottom*fy))
+	}
+}
@@ -0,0 +1,44 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/fogleman/gg"
+)
+
+// TestPerspective 测试按四角映射图片和按角度倾斜
+func TestPerspective(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(photo, image.Rect(0, 0, 50, 100), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+	draw.Draw(photo, image.Rect(50, 0, 100, 100), image.NewUniform(color.RGBA{0, 0, 255, 255}), image.Point{}, draw.Src)
+
+	combiner := NewImageCombiner(200, 200)
+	combiner.AddElement(&ImageElement{image: photo, ZoomMode: Origin, Alpha: 255,
+		Perspective: NewPerspective(gg.Point{X: 40, Y: 20}, gg.Point{X: 160, Y: 40}, gg.Point{X: 160, Y: 160}, gg.Point{X: 40, Y: 180})})
+	img, _ := combiner.Combine()
+
+	for p, want := range map[image.Point][3]uint32{
+		{50, 100}:  {255, 0, 0},
+		{150, 100}: {0, 0, 255},
+		{20, 100}:  {255, 255, 255},
+		{150, 25}:  {255, 255, 255}, // 右上角向下收窄
+	} {
+		if c := rgb(img.At(p.X, p.Y)); c != want {
+			t.Errorf("%v 应为 %v，实际 %v", p, want, c)
+		}
+	}
+
+	// 向右倾斜时右边缘变短
+	corners := (&Perspective{TiltX: 30}).corners(0, 0, 100, 100)
+	left, right := corners[3].Y-corners[0].Y, corners[2].Y-corners[1].Y
+	if right >= left {
+		t.Errorf("右边缘应短于左边缘: %.1f >= %.1f", right, left)
+	}
+
+	if _, ok := homography([4]gg.Point{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 3}}, corners); ok {
+		t.Error("共线的四点应无法求解")
+	}
+}
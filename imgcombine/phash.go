@@ -0,0 +1,75 @@
+package imgcombine
+
+import (
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+
+	"github.com/nfnt/resize"
+)
+
+// PHash 合成图片并返回其64位感知哈希，用于检测近似重复的海报
+func (ic *ImageCombiner) PHash() (uint64, error) {
+	img, err := ic.Combine()
+	if err != nil {
+		return 0, err
+	}
+	return PerceptualHash(img), nil
+}
+
+// PerceptualHash 计算图片的64位DCT感知哈希
+// 图片缩小为32x32灰度后做二维DCT，取左上角8x8低频分量与其中位数比较得到各位
+func PerceptualHash(img image.Image) uint64 {
+	const size, low = 32, 8
+
+	small := resize.Resize(size, size, img, resize.Bilinear)
+	bounds := small.Bounds()
+
+	pixels := make([]float64, size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			r, g, b, _ := small.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			pixels[y*size+x] = 0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8)
+		}
+	}
+
+	// 预计算DCT余弦表
+	cosTable := make([]float64, low*size)
+	for u := 0; u < low; u++ {
+		for x := 0; x < size; x++ {
+			cosTable[u*size+x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * size))
+		}
+	}
+
+	coeffs := make([]float64, 0, low*low)
+	for v := 0; v < low; v++ {
+		for u := 0; u < low; u++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					sum += pixels[y*size+x] * cosTable[u*size+x] * cosTable[v*size+y]
+				}
+			}
+			coeffs = append(coeffs, sum)
+		}
+	}
+
+	// 中位数不计入直流分量，避免整体亮度主导结果
+	sorted := append([]float64{}, coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2] // 共63个系数，取正中一个
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// HammingDistance 返回两个感知哈希之间不同的位数，数值越小图片越相似
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,38 @@
+package imgcombine
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestPHash 测试近似图片的感知哈希距离小，不同图片距离大
+func TestPHash(t *testing.T) {
+	poster := func(textX int, rectColor color.Color) uint64 {
+		combiner := NewImageCombiner(400, 300)
+		rect := combiner.AddRectangleElement(0, 0, 200, 300)
+		rect.Color = rectColor
+		circle := combiner.AddRectangleElement(250, 50, 100, 100)
+		circle.Color = color.RGBA{0, 0, 255, 255}
+		circle.RoundCorner = 50
+		combiner.AddTextElement("海报", 30, textX, 250)
+		hash, err := combiner.PHash()
+		if err != nil {
+			t.Fatalf("计算感知哈希失败: %v", err)
+		}
+		return hash
+	}
+
+	base := poster(250, color.RGBA{255, 0, 0, 255})
+	similar := poster(252, color.RGBA{250, 0, 0, 255})
+	different := NewImageCombiner(400, 300)
+	stripe := different.AddRectangleElement(0, 0, 400, 100)
+	stripe.Color = color.RGBA{0, 128, 0, 255}
+	other, _ := different.PHash()
+
+	if d := HammingDistance(base, similar); d > 5 {
+		t.Errorf("近似图片的哈希距离过大: %d", d)
+	}
+	if d := HammingDistance(base, other); d < 10 {
+		t.Errorf("不同图片的哈希距离过小: %d", d)
+	}
+}
@@ -0,0 +1,391 @@
+package imgcombine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrPipelineStopped 流水线停止时尚未开始构建的任务返回此错误
+var ErrPipelineStopped = errors.New("pipeline stopped before job started")
+
+// ErrDrainTimeout 流水线停止后，超过DrainTimeout仍未完成的任务返回此错误
+var ErrDrainTimeout = errors.New("pipeline drain timed out before job finished")
+
+// ErrJobPanicked 任务的构建或编码发生panic时返回此错误，其余任务不受影响
+var ErrJobPanicked = errors.New("pipeline job panicked")
+
+// PipelineJob 流水线任务
+type PipelineJob struct {
+	ID       string                         // 任务标识，原样返回在结果中
+	Build    func() (*ImageCombiner, error) // 加载素材并构建合成器，在解码阶段执行
+	Priority int                            // 优先级，数值大的任务先构建，默认0
+	Tenant   string                         // 租户标识，同一优先级内各租户的任务轮流构建
+	seq      uint64                         // 接收序号，用于跟踪未完成的任务
+}
+
+// PipelineResult 流水线任务结果
+type PipelineResult struct {
+	ID   string // 任务标识
+	Data []byte // 编码后的图片
+	Err  error  // 构建、审核或编码错误
+}
+
+// Pipeline 批量渲染流水线，后续任务的素材加载与当前任务的合成编码并行执行
+// 已接收、等待构建的任务数不超过Decoders+Buffer，已构建、等待编码的合成器数量受Buffer限制，
+// 同时驻留内存的合成器数不超过Decoders+Buffer+Encoders
+type Pipeline struct {
+	Decoders     int           // 并发构建（加载、解码素材）的任务数，默认1
+	Encoders     int           // 并发合成与编码的任务数，默认1
+	Buffer       int           // 已构建、等待编码的合成器数量上限，默认1
+	DrainTimeout time.Duration // 停止后等待进行中任务完成的最长时间，0表示一直等待
+}
+
+// builtJob 已构建、等待编码的任务
+type builtJob struct {
+	id       string
+	seq      uint64
+	combiner *ImageCombiner
+	err      error
+}
+
+// finishedJob 已完成的任务结果
+type finishedJob struct {
+	seq    uint64
+	result PipelineResult
+}
+
+// Run 启动流水线，处理jobs中的任务，jobs关闭且全部任务完成后关闭返回的结果通道
+// 已接收、未构建的任务（最多Decoders+Buffer个）按优先级从高到低调度，同一优先级内按租户轮流调度，
+// 大批量任务不会阻塞其他租户的少量交互任务；同一租户同一优先级的任务按接收顺序构建
+// 多个编码协程时结果顺序可能与任务顺序不同，以ID对应
+func (p *Pipeline) Run(jobs <-chan PipelineJob) <-chan PipelineResult {
+	return p.RunContext(context.Background(), jobs)
+}
+
+// RunContext 与Run相同，ctx取消后优雅停止：不再从jobs接收任务，已开始构建和已构建的任务继续完成编码，
+// 已接收但未开始构建的任务，以及取消时已在jobs中等待的任务，以ErrPipelineStopped返回，便于调用方记录或重新入队；
+// 设置了DrainTimeout时，超时时已完成的结果照常返回，仍未完成的任务以ErrDrainTimeout返回，不再等待其结果，随后关闭结果通道
+// 配合signal.NotifyContext可在进程收到SIGTERM时排空流水线，调用方应读完结果通道
+func (p *Pipeline) RunContext(ctx context.Context, jobs <-chan PipelineJob) <-chan PipelineResult {
+	decoders, encoders := max(p.Decoders, 1), max(p.Encoders, 1)
+	built := make(chan builtJob, max(p.Buffer, 1))
+	done := make(chan finishedJob)
+	results := make(chan PipelineResult, encoders)
+	tracker := newJobTracker()
+
+	// 持续接收任务放入调度队列，使后到的高优先级任务可以越过已排队的任务
+	// 队列中的任务数达到Decoders+Buffer时暂停接收，jobs的发送方随之阻塞，内存占用不随任务总数增长
+	queue := newJobQueue()
+	slots := make(chan struct{}, decoders+max(p.Buffer, 1))
+	accept := func(job PipelineJob) {
+		job.seq = tracker.add(job.ID)
+		queue.push(job)
+	}
+	go func() {
+		defer queue.close()
+		for {
+			select {
+			case <-ctx.Done():
+				// 取消时已在jobs中等待的任务同样接收，以ErrPipelineStopped返回
+				for {
+					select {
+					case job, ok := <-jobs:
+						if !ok {
+							return
+						}
+						accept(job)
+					default:
+						return
+					}
+				}
+			case slots <- struct{}{}:
+			}
+			select {
+			case <-ctx.Done():
+				<-slots
+			case job, ok := <-jobs:
+				if !ok {
+					return
+				}
+				accept(job)
+			}
+		}
+	}()
+
+	var decodeWG sync.WaitGroup
+	for i := 0; i < decoders; i++ {
+		decodeWG.Add(1)
+		go func() {
+			defer decodeWG.Done()
+			for {
+				job, ok := queue.pop()
+				if !ok {
+					return
+				}
+				// 取消时排空jobs接收的任务没有占用名额
+				select {
+				case <-slots:
+				default:
+				}
+				if ctx.Err() != nil {
+					built <- builtJob{id: job.ID, seq: job.seq, err: ErrPipelineStopped}
+					continue
+				}
+				tracker.start(job.seq)
+				combiner, err := buildJob(job)
+				built <- builtJob{id: job.ID, seq: job.seq, combiner: combiner, err: err}
+			}
+		}()
+	}
+	go func() {
+		decodeWG.Wait()
+		close(built)
+	}()
+
+	var encodeWG sync.WaitGroup
+	for i := 0; i < encoders; i++ {
+		encodeWG.Add(1)
+		go func() {
+			defer encodeWG.Done()
+			for job := range built {
+				result := PipelineResult{ID: job.id, Err: job.err}
+				if job.err == nil {
+					result.Data, result.Err = encodeJob(job.combiner)
+				}
+				tracker.complete(job.seq)
+				done <- finishedJob{seq: job.seq, result: result}
+			}
+		}()
+	}
+	go func() {
+		encodeWG.Wait()
+		close(done)
+	}()
+
+	// 转发结果，取消后超过DrainTimeout时报告未完成的任务并关闭结果通道，之后完成的结果被丢弃
+	go func() {
+		defer close(results)
+		var timeout <-chan time.Time
+		cancelled := ctx.Done()
+		for {
+			select {
+			case job, ok := <-done:
+				if !ok {
+					return
+				}
+				tracker.finish(job.seq)
+				results <- job.result
+			case <-cancelled:
+				cancelled = nil
+				if p.DrainTimeout > 0 {
+					timeout = time.After(p.DrainTimeout)
+				}
+			case <-timeout:
+				// 已完成、尚未转发的结果照常返回，只有仍在构建或编码的任务报告超时
+				for tracker.hasCompleted() {
+					job, ok := <-done
+					if !ok {
+						return
+					}
+					tracker.finish(job.seq)
+					results <- job.result
+				}
+				for _, result := range tracker.abandon() {
+					results <- result
+				}
+				go func() {
+					for range done {
+					}
+				}()
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// buildJob 执行任务的构建，panic转换为ErrJobPanicked错误
+func buildJob(job PipelineJob) (combiner *ImageCombiner, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: build: %v", ErrJobPanicked, r)
+		}
+	}()
+	return job.Build()
+}
+
+// encodeJob 合成并编码，panic转换为ErrJobPanicked错误
+func encodeJob(combiner *ImageCombiner) (data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: encode: %v", ErrJobPanicked, r)
+		}
+	}()
+	return combiner.ToBytes()
+}
+
+// jobTracker 跟踪已接收、尚未返回结果的任务
+type jobTracker struct {
+	mu   sync.Mutex
+	next uint64
+	jobs map[uint64]*trackedJob
+}
+
+// trackedJob 未完成的任务
+type trackedJob struct {
+	id        string
+	started   bool // 已开始构建
+	completed bool // 已完成，结果等待转发
+}
+
+func newJobTracker() *jobTracker {
+	return &jobTracker{jobs: make(map[uint64]*trackedJob)}
+}
+
+// add 登记新接收的任务，返回其序号
+func (t *jobTracker) add(id string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	t.jobs[t.next] = &trackedJob{id: id}
+	return t.next
+}
+
+// start 标记任务已开始构建
+func (t *jobTracker) start(seq uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if job := t.jobs[seq]; job != nil {
+		job.started = true
+	}
+}
+
+// complete 标记任务已完成，结果即将转发
+func (t *jobTracker) complete(seq uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if job := t.jobs[seq]; job != nil {
+		job.completed = true
+	}
+}
+
+// hasCompleted 判断是否有已完成、尚未转发结果的任务
+func (t *jobTracker) hasCompleted() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, job := range t.jobs {
+		if job.completed {
+			return true
+		}
+	}
+	return false
+}
+
+// finish 移除已返回结果的任务
+func (t *jobTracker) finish(seq uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.jobs, seq)
+}
+
+// abandon 按接收顺序返回全部未完成任务的结果：已开始构建的为ErrDrainTimeout，其余为ErrPipelineStopped
+func (t *jobTracker) abandon() []PipelineResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seqs := slices.Sorted(maps.Keys(t.jobs))
+	results := make([]PipelineResult, len(seqs))
+	for i, seq := range seqs {
+		err := ErrPipelineStopped
+		if t.jobs[seq].started {
+			err = ErrDrainTimeout
+		}
+		results[i] = PipelineResult{ID: t.jobs[seq].id, Err: err}
+	}
+	clear(t.jobs)
+	return results
+}
+
+// jobQueue 按优先级和租户公平调度的任务队列
+type jobQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	levels map[int]*tenantQueue
+	closed bool
+}
+
+// tenantQueue 同一优先级的任务，按租户轮流出队
+type tenantQueue struct {
+	tenants []string                 // 有待处理任务的租户，按轮转顺序排列
+	pending map[string][]PipelineJob // 各租户待处理的任务
+}
+
+func newJobQueue() *jobQueue {
+	q := &jobQueue{levels: make(map[int]*tenantQueue)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push 将任务放入对应优先级和租户的队列
+func (q *jobQueue) push(job PipelineJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	level := q.levels[job.Priority]
+	if level == nil {
+		level = &tenantQueue{pending: make(map[string][]PipelineJob)}
+		q.levels[job.Priority] = level
+	}
+	if len(level.pending[job.Tenant]) == 0 {
+		level.tenants = append(level.tenants, job.Tenant)
+	}
+	level.pending[job.Tenant] = append(level.pending[job.Tenant], job)
+	q.cond.Signal()
+}
+
+// close 标记不再有新任务，队列取空后pop返回false
+func (q *jobQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.cond.Broadcast()
+}
+
+// pop 取出最高优先级中轮到的租户的下一个任务，队列为空时阻塞
+func (q *jobQueue) pop() (PipelineJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.levels) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if len(q.levels) == 0 {
+		return PipelineJob{}, false
+	}
+
+	priority, first := 0, true
+	for p := range q.levels {
+		if first || p > priority {
+			priority, first = p, false
+		}
+	}
+	level := q.levels[priority]
+
+	// 取队首租户的任务，该租户仍有任务时移到队尾
+	tenant := level.tenants[0]
+	level.tenants = level.tenants[1:]
+	job := level.pending[tenant][0]
+	if rest := level.pending[tenant][1:]; len(rest) > 0 {
+		level.pending[tenant] = rest
+		level.tenants = append(level.tenants, tenant)
+	} else {
+		delete(level.pending, tenant)
+	}
+	if len(level.tenants) == 0 {
+		delete(q.levels, priority)
+	}
+	return job, true
+}
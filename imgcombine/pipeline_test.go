@@ -0,0 +1,304 @@
+package imgcombine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPipeline 测试流水线处理全部任务、传递构建错误并限制并发构建数
+func TestPipeline(t *testing.T) {
+	var building, peak int32
+	jobs := make(chan PipelineJob)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < 8; i++ {
+			id := fmt.Sprintf("job-%d", i)
+			jobs <- PipelineJob{ID: id, Build: func() (*ImageCombiner, error) {
+				n := atomic.AddInt32(&building, 1)
+				defer atomic.AddInt32(&building, -1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				if id == "job-3" {
+					return nil, errors.New("asset missing")
+				}
+				combiner := NewImageCombiner(50, 50)
+				combiner.AddRectangleElement(0, 0, 10, 10)
+				return combiner, nil
+			}}
+		}
+	}()
+
+	pipeline := &Pipeline{Decoders: 2, Encoders: 2, Buffer: 2}
+	seen := map[string]bool{}
+	for result := range pipeline.Run(jobs) {
+		seen[result.ID] = true
+		switch {
+		case result.ID == "job-3":
+			if result.Err == nil {
+				t.Error("构建错误应传递到结果")
+			}
+		case result.Err != nil:
+			t.Errorf("%s 失败: %v", result.ID, result.Err)
+		case len(result.Data) == 0:
+			t.Errorf("%s 输出为空", result.ID)
+		}
+	}
+	if len(seen) != 8 {
+		t.Errorf("应返回8个结果，实际 %d", len(seen))
+	}
+	if peak > 2 {
+		t.Errorf("同时构建的任务数 %d 超出Decoders限制", peak)
+	}
+}
+
+// TestPipelineBackpressure 测试构建阻塞时流水线只接收有限的任务，发送方随之阻塞
+func TestPipelineBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	var sent int32
+	jobs := make(chan PipelineJob)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < 100; i++ {
+			jobs <- PipelineJob{ID: fmt.Sprint(i), Build: func() (*ImageCombiner, error) {
+				<-release
+				return NewImageCombiner(10, 10), nil
+			}}
+			atomic.AddInt32(&sent, 1)
+		}
+	}()
+
+	pipeline := &Pipeline{Decoders: 2, Buffer: 3}
+	results := pipeline.Run(jobs)
+	time.Sleep(20 * time.Millisecond)
+	// 2个构建中的任务加上队列中的Decoders+Buffer个
+	if n := atomic.LoadInt32(&sent); n > 2+5 {
+		t.Errorf("构建阻塞时不应继续接收任务，已接收 %d 个", n)
+	}
+	close(release)
+
+	count := 0
+	for range results {
+		count++
+	}
+	if count != 100 {
+		t.Errorf("应完成100个任务，实际 %d", count)
+	}
+}
+
+// TestPipelineScheduling 测试任务按优先级调度，同一优先级内各租户轮流
+func TestPipelineScheduling(t *testing.T) {
+	queue := newJobQueue()
+	for i := 0; i < 3; i++ {
+		queue.push(PipelineJob{ID: fmt.Sprintf("batch-%d", i), Tenant: "campaign"})
+	}
+	queue.push(PipelineJob{ID: "shop-0", Tenant: "shop"})
+	queue.push(PipelineJob{ID: "shop-1", Tenant: "shop"})
+	queue.push(PipelineJob{ID: "urgent", Tenant: "shop", Priority: 1})
+	queue.close()
+
+	var order []string
+	for {
+		job, ok := queue.pop()
+		if !ok {
+			break
+		}
+		order = append(order, job.ID)
+	}
+	want := []string{"urgent", "batch-0", "shop-0", "batch-1", "shop-1", "batch-2"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("调度顺序应为 %v，实际 %v", want, order)
+	}
+
+	// 流水线中后到的交互任务越过已排队的批量任务
+	started, release := make(chan struct{}), make(chan struct{})
+	var built []string
+	job := func(id string, priority int, tenant string) PipelineJob {
+		return PipelineJob{ID: id, Priority: priority, Tenant: tenant, Build: func() (*ImageCombiner, error) {
+			if id == "first" {
+				close(started)
+				<-release
+			}
+			built = append(built, id)
+			return NewImageCombiner(10, 10), nil
+		}}
+	}
+	jobs := make(chan PipelineJob, 8)
+	results := (&Pipeline{Buffer: 4}).Run(jobs)
+	jobs <- job("first", 0, "campaign")
+	<-started
+	for i := 0; i < 4; i++ {
+		jobs <- job(fmt.Sprintf("batch-%d", i), 0, "campaign")
+	}
+	jobs <- job("interactive", 5, "shop")
+	close(jobs)
+	// 等待调度协程接收全部任务后再放行第一个任务
+	for len(jobs) > 0 {
+		runtime.Gosched()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	count := 0
+	for range results {
+		count++
+	}
+	if count != 6 {
+		t.Fatalf("应完成6个任务，实际 %d", count)
+	}
+	if built[1] != "interactive" {
+		t.Errorf("交互任务应在批量任务之前构建: %v", built)
+	}
+}
+
+// TestPipelineShutdown 测试取消后完成进行中的任务，未开始的任务以ErrPipelineStopped返回
+func TestPipelineShutdown(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var builds int32
+	job := func(id string) PipelineJob {
+		return PipelineJob{ID: id, Build: func() (*ImageCombiner, error) {
+			atomic.AddInt32(&builds, 1)
+			if id == "running" {
+				close(started)
+				<-release
+			}
+			return NewImageCombiner(10, 10), nil
+		}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan PipelineJob, 4)
+	results := (&Pipeline{Buffer: 2}).RunContext(ctx, jobs)
+	jobs <- job("running")
+	<-started
+	for i := 0; i < 3; i++ {
+		jobs <- job(fmt.Sprintf("queued-%d", i))
+	}
+	for len(jobs) > 0 {
+		runtime.Gosched()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	close(release)
+
+	// jobs未关闭，取消后结果通道仍应关闭
+	stopped := 0
+	for result := range results {
+		switch {
+		case result.ID == "running":
+			if result.Err != nil || len(result.Data) == 0 {
+				t.Errorf("进行中的任务应完成: %v", result.Err)
+			}
+		case errors.Is(result.Err, ErrPipelineStopped):
+			stopped++
+		default:
+			t.Errorf("%s 应返回ErrPipelineStopped，实际 %v", result.ID, result.Err)
+		}
+	}
+	if stopped != 3 {
+		t.Errorf("应有3个未开始的任务，实际 %d", stopped)
+	}
+	if n := atomic.LoadInt32(&builds); n != 1 {
+		t.Errorf("取消后不应再构建任务，实际构建 %d 个", n)
+	}
+}
+
+// TestPipelineDrain 测试停止时报告未接收的任务、排空超时和单个任务的panic
+func TestPipelineDrain(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	job := func(id string) PipelineJob {
+		return PipelineJob{ID: id, Build: func() (*ImageCombiner, error) {
+			if id == "stuck" {
+				close(started)
+				<-release
+			}
+			return NewImageCombiner(10, 10), nil
+		}}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan PipelineJob, 8)
+	results := (&Pipeline{DrainTimeout: 20 * time.Millisecond}).RunContext(ctx, jobs)
+	jobs <- job("stuck")
+	<-started
+	// 队列只容纳Decoders+Buffer个任务，其余留在jobs中
+	for i := 0; i < 4; i++ {
+		jobs <- job(fmt.Sprintf("queued-%d", i))
+	}
+	for len(jobs) > 2 {
+		runtime.Gosched()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	errs := map[string]error{}
+	timer := time.AfterFunc(time.Second, func() { panic("结果通道未在排空超时后关闭") })
+	for result := range results {
+		errs[result.ID] = result.Err
+	}
+	timer.Stop()
+	if !errors.Is(errs["stuck"], ErrDrainTimeout) {
+		t.Errorf("超时未完成的任务应返回ErrDrainTimeout，实际 %v", errs["stuck"])
+	}
+	for i := 0; i < 4; i++ {
+		if id := fmt.Sprintf("queued-%d", i); !errors.Is(errs[id], ErrPipelineStopped) {
+			t.Errorf("%s 应返回ErrPipelineStopped，实际 %v", id, errs[id])
+		}
+	}
+
+	// 超时时已完成、因调用方读取较慢尚未转发的结果照常返回
+	ctx, cancel = context.WithCancel(context.Background())
+	jobs = make(chan PipelineJob, 5)
+	results = (&Pipeline{Decoders: 2, Buffer: 4, DrainTimeout: 10 * time.Millisecond}).RunContext(ctx, jobs)
+	for i := 0; i < 4; i++ {
+		jobs <- job(fmt.Sprintf("done-%d", i))
+	}
+	started = make(chan struct{})
+	jobs <- job("stuck")
+	<-started
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	errs = map[string]error{}
+	for result := range results {
+		errs[result.ID] = result.Err
+		time.Sleep(20 * time.Millisecond)
+	}
+	for i := 0; i < 4; i++ {
+		if id := fmt.Sprintf("done-%d", i); errs[id] != nil {
+			t.Errorf("%s 已完成，不应报告 %v", id, errs[id])
+		}
+	}
+	if !errors.Is(errs["stuck"], ErrDrainTimeout) {
+		t.Errorf("超时未完成的任务应返回ErrDrainTimeout，实际 %v", errs["stuck"])
+	}
+
+	// 构建或编码时panic只影响该任务
+	jobs = make(chan PipelineJob, 3)
+	jobs <- PipelineJob{ID: "build", Build: func() (*ImageCombiner, error) { panic("boom") }}
+	jobs <- PipelineJob{ID: "encode", Build: func() (*ImageCombiner, error) {
+		combiner := NewImageCombiner(10, 10)
+		combiner.AddElement(nil)
+		return combiner, nil
+	}}
+	jobs <- job("ok")
+	close(jobs)
+	errs = map[string]error{}
+	for result := range (&Pipeline{}).Run(jobs) {
+		errs[result.ID] = result.Err
+	}
+	if !errors.Is(errs["build"], ErrJobPanicked) || !errors.Is(errs["encode"], ErrJobPanicked) {
+		t.Errorf("panic应转换为ErrJobPanicked: %v %v", errs["build"], errs["encode"])
+	}
+	if err, ok := errs["ok"]; !ok || err != nil {
+		t.Errorf("其他任务应正常完成: %v", err)
+	}
+}
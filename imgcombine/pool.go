@@ -0,0 +1,53 @@
+package imgcombine
+
+import (
+	"image"
+	"sync"
+)
+
+// layerPool 复用绘制文本透明度、渐变和图层缓存时使用的临时全画布图层
+// 按尺寸分池，避免批量渲染时每个元素都分配一块画布大小的内存
+var layerPool sync.Map // image.Point -> *sync.Pool
+
+// getLayer 取得指定尺寸的透明图层
+func getLayer(width, height int) *image.RGBA {
+	pool, _ := layerPool.LoadOrStore(image.Pt(width, height), &sync.Pool{})
+	if img, ok := pool.(*sync.Pool).Get().(*image.RGBA); ok {
+		clear(img.Pix)
+		return img
+	}
+	return image.NewRGBA(image.Rect(0, 0, width, height))
+}
+
+// putLayer 归还图层，归还后调用方不得再引用
+func putLayer(img *image.RGBA) {
+	pool, _ := layerPool.LoadOrStore(image.Pt(img.Rect.Dx(), img.Rect.Dy()), &sync.Pool{})
+	pool.(*sync.Pool).Put(img)
+}
+
+// releaser 持有已解码图片等大块内存的元素
+type releaser interface {
+	release()
+}
+
+// release 丢弃缓存的图片，之后的绘制不再输出该元素
+func (ie *ImageElement) release() {
+	ie.image = nil
+	ie.retouched = nil
+	ie.cutout = nil
+	ie.svg = nil
+	ie.raster = nil
+}
+
+// Release 释放合成器持有的元素和已解码图片，长期运行的服务在输出结果后调用，
+// 即使调用方仍引用元素也能及时回收图片内存；释放后合成器不能再使用
+func (ic *ImageCombiner) Release() {
+	for _, element := range ic.elements {
+		if r, ok := element.(releaser); ok {
+			r.release()
+		}
+	}
+	ic.elements = nil
+	ic.context = nil
+	ic.processed = nil
+}
@@ -0,0 +1,63 @@
+package imgcombine
+
+import (
+	"image"
+	"reflect"
+)
+
+// processCache 图片元素的修饰、抠图等处理结果，保存在合成器上而不写入元素，同一元素可被多个合成器使用
+// 源图片、选项或钩子变化时重新处理；不可比较的钩子（如RetoucherFunc）无法判断是否被替换，
+// 其结果只在单次合成或单次逐帧渲染内复用
+type processCache struct {
+	run     uint64 // 当前渲染序号，单次合成或单次逐帧渲染递增一次
+	entries map[processKey]processed
+}
+
+// processKey 处理结果的键：元素及处理类型
+type processKey struct {
+	element *ImageElement
+	kind    string
+}
+
+// processed 处理结果及产生它的输入
+type processed struct {
+	src  image.Image
+	opts any
+	hook any // 可比较的钩子，不可比较时为nil
+	run  uint64
+	img  image.Image
+}
+
+// processCache 返回合成器的处理结果缓存
+func (ic *ImageCombiner) processCache() *processCache {
+	if ic.processed == nil {
+		ic.processed = &processCache{entries: make(map[processKey]processed)}
+	}
+	return ic.processed
+}
+
+// get 返回src按opts和hook处理的结果，缓存中的结果输入一致时直接返回，否则调用process重新处理
+func (c *processCache) get(key processKey, src image.Image, opts, hook any, process func() (image.Image, error)) (image.Image, error) {
+	if !isComparable(hook) {
+		hook = nil
+	}
+	if p, ok := c.entries[key]; ok && identical(p.src, src) && p.opts == opts && (p.run == c.run || hook != nil && p.hook == hook) {
+		return p.img, nil
+	}
+	img, err := process()
+	if err != nil {
+		return nil, err
+	}
+	c.entries[key] = processed{src: src, opts: opts, hook: hook, run: c.run, img: img}
+	return img, nil
+}
+
+// isComparable 判断v的动态类型是否可以用==比较
+func isComparable(v any) bool {
+	return v != nil && reflect.TypeOf(v).Comparable()
+}
+
+// identical 判断两个图片是否为同一对象
+func identical(a, b image.Image) bool {
+	return isComparable(a) && isComparable(b) && a == b
+}
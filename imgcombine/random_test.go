@@ -0,0 +1,65 @@
+package imgcombine
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// TestSeededRender 测试设置种子后生成式元素可复现
+func TestSeededRender(t *testing.T) {
+	render := func(key string) []byte {
+		combiner := NewImageCombiner(200, 200)
+		combiner.OutputFormat = PNG
+		combiner.SetSeedString(key)
+		// 构建阶段使用合成器随机数决定位置
+		x := combiner.Rand().Intn(100)
+		combiner.AddConfettiElement(ParticleBokeh, 20, x, 0, 100, 200)
+		data, err := combiner.ToBytes()
+		if err != nil {
+			t.Fatalf("合成失败: %v", err)
+		}
+		return data
+	}
+
+	if !bytes.Equal(render("user-1"), render("user-1")) {
+		t.Error("相同种子的渲染结果应一致")
+	}
+	if bytes.Equal(render("user-1"), render("user-2")) {
+		t.Error("不同种子的渲染结果应不同")
+	}
+
+	// 同一合成器多次合成结果一致
+	combiner := NewImageCombiner(100, 100)
+	combiner.OutputFormat = PNG
+	combiner.SetSeed(7)
+	combiner.AddConfettiElement(ParticleConfetti, 20, 0, 0, 100, 100)
+	first, _ := combiner.ToBytes()
+	second, _ := combiner.ToBytes()
+	if !bytes.Equal(first, second) {
+		t.Error("同一合成器重复合成的结果应一致")
+	}
+
+	// 共享元素的合成器并发渲染互不影响，结果与各自单独渲染一致
+	shared := &ConfettiElement{Width: 100, Height: 100, Count: 20, MinSize: 6, MaxSize: 16}
+	combiners := make([]*ImageCombiner, 2)
+	want := make([][]byte, len(combiners))
+	for i := range combiners {
+		combiners[i] = NewImageCombiner(100, 100)
+		combiners[i].OutputFormat = PNG
+		combiners[i].SetSeed(int64(i + 1))
+		combiners[i].AddElement(shared)
+		want[i], _ = combiners[i].ToBytes()
+	}
+	var wg sync.WaitGroup
+	for i, c := range combiners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, _ := c.ToBytes(); !bytes.Equal(got, want[i]) {
+				t.Errorf("合成器%d并发渲染结果与单独渲染不一致", i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,42 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+)
+
+// Reflection 图片倒影：在图片下方绘制垂直翻转并逐渐淡出的副本，常用于商品展示
+type Reflection struct {
+	Gap     int     // 倒影与图片的间距(像素)
+	Opacity int     // 倒影起始透明度(0-255)，向下淡出至完全透明
+	Height  float64 // 倒影高度占图片高度的比例(0-1)，0表示使用0.5
+}
+
+// NewReflection 创建默认倒影：紧贴图片，起始透明度约40%，高度为图片的一半
+func NewReflection() *Reflection {
+	return &Reflection{Opacity: 100, Height: 0.5}
+}
+
+// image 由已缩放并处理过圆角、透明度的图片生成倒影
+func (r *Reflection) image(img image.Image) image.Image {
+	bounds := img.Bounds()
+	fraction := r.Height
+	if fraction <= 0 || fraction > 1 {
+		fraction = 0.5
+	}
+	height := int(float64(bounds.Dy()) * fraction)
+	if height <= 0 || r.Opacity <= 0 {
+		return nil
+	}
+
+	// 倒影第y行对应原图倒数第y+1行
+	flipped := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			flipped.Set(x, y, img.At(bounds.Min.X+x, bounds.Max.Y-1-y))
+		}
+	}
+
+	fade := NewLinearGradient(90, color.NRGBA{A: uint8(min(r.Opacity, 255))}, color.NRGBA{})
+	return fade.applyMask(flipped)
+}
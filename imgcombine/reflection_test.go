@@ -0,0 +1,34 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// TestReflection 测试倒影为翻转后逐渐淡出的副本
+func TestReflection(t *testing.T) {
+	photo := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	draw.Draw(photo, image.Rect(0, 0, 100, 50), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+	draw.Draw(photo, image.Rect(0, 50, 100, 100), image.NewUniform(color.RGBA{0, 0, 255, 255}), image.Point{}, draw.Src)
+
+	combiner := NewImageCombiner(100, 200)
+	combiner.AddElement(&ImageElement{image: photo, ZoomMode: Origin, Alpha: 255,
+		Reflection: &Reflection{Gap: 10, Opacity: 255, Height: 0.5}})
+	img, _ := combiner.Combine()
+
+	if c := rgb(img.At(50, 105)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("间距内应为背景，实际 %v", c)
+	}
+	// 倒影首行为图片底边（蓝色），随后淡出
+	if c := rgb(img.At(50, 110)); c[2] < 240 || c[0] > 20 {
+		t.Errorf("倒影首行应接近原图底边颜色，实际 %v", c)
+	}
+	if c := rgb(img.At(50, 135)); c[0] < 100 || c[0] > 155 {
+		t.Errorf("倒影中部应半透明，实际 %v", c)
+	}
+	if c := rgb(img.At(50, 165)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("倒影高度为图片一半，之后应为背景，实际 %v", c)
+	}
+}
@@ -0,0 +1,57 @@
+package imgcombine
+
+import (
+	"image"
+
+	"github.com/nfnt/resize"
+)
+
+// ResizeFilter 图片缩放插值算法枚举
+type ResizeFilter string
+
+const (
+	DefaultFilter   ResizeFilter = ""         // 使用DefaultResizeFilter
+	NearestNeighbor ResizeFilter = "nearest"  // 最近邻：保留硬边缘，用于像素画
+	Bilinear        ResizeFilter = "bilinear" // 双线性：速度快，用于大批量任务
+	Bicubic         ResizeFilter = "bicubic"  // 双三次
+	Lanczos3        ResizeFilter = "lanczos3" // Lanczos3：质量最高，由加速后端的Resize实现
+)
+
+// DefaultResizeFilter 图片元素未指定ResizeFilter时使用的插值算法
+var DefaultResizeFilter = Lanczos3
+
+// FilterResizer 支持按插值算法缩放的加速后端，未实现时Lanczos3以外的算法使用纯Go实现
+type FilterResizer interface {
+	ResizeFilter(img image.Image, width, height int, filter ResizeFilter) image.Image
+}
+
+// resizeWith 按插值算法缩放图片，Lanczos3交由当前加速后端的Resize处理
+// 图片已是目标尺寸时直接返回原图，避免无谓的计算和插值造成的轻微模糊
+func resizeWith(img image.Image, width, height int, filter ResizeFilter) image.Image {
+	if b := img.Bounds(); b.Min == (image.Point{}) && b.Dx() == width && b.Dy() == height {
+		return img
+	}
+	if filter == DefaultFilter {
+		filter = DefaultResizeFilter
+	}
+	accel := CurrentAccelerator()
+	if filter == Lanczos3 {
+		return accel.Resize(img, width, height)
+	}
+	if fr, ok := accel.(FilterResizer); ok {
+		return fr.ResizeFilter(img, width, height, filter)
+	}
+
+	var interp resize.InterpolationFunction
+	switch filter {
+	case NearestNeighbor:
+		interp = resize.NearestNeighbor
+	case Bilinear:
+		interp = resize.Bilinear
+	case Bicubic:
+		interp = resize.Bicubic
+	default:
+		return accel.Resize(img, width, height)
+	}
+	return resize.Resize(uint(width), uint(height), img, interp)
+}
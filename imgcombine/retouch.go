@@ -0,0 +1,111 @@
+package imgcombine
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// RetouchOptions 人像修饰选项
+type RetouchOptions struct {
+	RedEye     bool    // 去除红眼
+	SmoothSkin float64 // 磨皮强度(0-1)，0不磨皮
+}
+
+// Retoucher 人像修饰钩子，返回修饰后的图片
+// 内置实现基于颜色规则，可替换为接入人脸检测的实现以获得更精确的效果
+type Retoucher interface {
+	Retouch(img image.Image, opts RetouchOptions) (image.Image, error)
+}
+
+// RetoucherFunc 函数形式的Retoucher
+type RetoucherFunc func(img image.Image, opts RetouchOptions) (image.Image, error)
+
+// Retouch 实现Retoucher接口
+func (f RetoucherFunc) Retouch(img image.Image, opts RetouchOptions) (image.Image, error) {
+	return f(img, opts)
+}
+
+// DefaultRetoucher 合成器未设置Retoucher时使用的内置实现
+// 假定图片为头像等人像特写：红眼只在眼睛可能出现的中上部区域内查找，
+// 磨皮只作用于肤色像素，以模糊后的图片按强度混合
+var DefaultRetoucher Retoucher = RetoucherFunc(simpleRetouch)
+
+// simpleRetouch 内置的人像修饰实现
+func simpleRetouch(img image.Image, opts RetouchOptions) (image.Image, error) {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	w, h := rgba.Rect.Dx(), rgba.Rect.Dy()
+
+	if opts.SmoothSkin > 0 {
+		amount := math.Min(opts.SmoothSkin, 1)
+		blurred := image.NewRGBA(rgba.Rect)
+		draw.Draw(blurred, blurred.Rect, CurrentAccelerator().Blur(rgba, math.Max(1, float64(min(w, h))/80)), image.Point{}, draw.Src)
+		for i := 0; i < len(rgba.Pix); i += 4 {
+			if !isSkin(rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2]) {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				rgba.Pix[i+c] = clampChannel(float64(rgba.Pix[i+c])*(1-amount) + float64(blurred.Pix[i+c])*amount)
+			}
+		}
+	}
+
+	if opts.RedEye {
+		// 眼睛位于人像特写的中上部，红色明显高于绿、蓝通道时将红色降为绿蓝均值
+		for y := h / 5; y < h*3/5; y++ {
+			for x := w / 8; x < w*7/8; x++ {
+				i := rgba.PixOffset(x, y)
+				r, g, b := float64(rgba.Pix[i]), float64(rgba.Pix[i+1]), float64(rgba.Pix[i+2])
+				if gb := (g + b) / 2; r > 80 && r > gb*2 {
+					rgba.Pix[i] = clampChannel(gb)
+				}
+			}
+		}
+	}
+	return rgba, nil
+}
+
+// isSkin 按常用的RGB肤色规则判断像素是否为肤色
+func isSkin(r, g, b uint8) bool {
+	maxC, minC := max(r, g, b), min(r, g, b)
+	return r > 95 && g > 40 && b > 20 && maxC-minC > 15 && r > g+15 && r > b
+}
+
+// retouchImages 为设置了Retouch的图片元素执行人像修饰，将带修饰结果的元素副本按原元素放入prepared，
+// 合成时绘制副本，元素本身不被修改；结果缓存在合成器上，图片、选项或Retoucher变化时重新修饰
+// 首字母占位头像不做修饰
+func (ic *ImageCombiner) retouchImages(prepared map[*ImageElement]*ImageElement) error {
+	retoucher := ic.Retoucher
+	if retoucher == nil {
+		retoucher = DefaultRetoucher
+	}
+	cache := ic.processCache()
+	for _, element := range ic.elements {
+		ie, ok := element.(*ImageElement)
+		if !ok || ie.Retouch == nil || ie.image == nil || ie.placeholder {
+			continue
+		}
+		opts := *ie.Retouch
+		img, err := cache.get(processKey{ie, "retouch"}, ie.image, opts, retoucher, func() (image.Image, error) {
+			return retoucher.Retouch(ie.image, opts)
+		})
+		if err != nil {
+			return fmt.Errorf("retouch %s: %w", ie.ImagePath, err)
+		}
+		copied := *ie
+		copied.retouched = img
+		prepared[ie] = &copied
+	}
+	return nil
+}
+
+// base 返回修饰后的图片，未修饰时返回原图
+func (ie *ImageElement) base() image.Image {
+	if ie.retouched != nil {
+		return ie.retouched
+	}
+	return ie.image
+}
@@ -0,0 +1,112 @@
+package imgcombine
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+)
+
+// TestRetouch 测试内置去红眼和磨皮
+func TestRetouch(t *testing.T) {
+	skin := color.RGBA{220, 170, 140, 255}
+	photo := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			c := skin
+			// 皮肤上的细小斑点
+			if x%4 == 0 && y%4 == 0 {
+				c = color.RGBA{180, 120, 100, 255}
+			}
+			photo.Set(x, y, c)
+		}
+	}
+	photo.Set(40, 40, color.RGBA{200, 30, 40, 255}) // 红眼
+	photo.Set(40, 90, color.RGBA{200, 30, 40, 255}) // 眼睛区域以外的红色
+
+	img, err := DefaultRetoucher.Retouch(photo, RetouchOptions{RedEye: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, _, _ := img.At(40, 40).RGBA(); r>>8 > 60 {
+		t.Errorf("红眼应被去除，红色通道 %d", r>>8)
+	}
+	if r, _, _, _ := img.At(40, 90).RGBA(); r>>8 != 200 {
+		t.Errorf("眼睛区域以外的红色应保留，红色通道 %d", r>>8)
+	}
+
+	img, _ = DefaultRetoucher.Retouch(photo, RetouchOptions{SmoothSkin: 1})
+	before := int(skin.R) - 180
+	r, _, _, _ := img.At(20, 20).RGBA()
+	if after := int(skin.R) - int(r>>8); after >= before/2 {
+		t.Errorf("磨皮后斑点应明显减淡，与肤色的差值 %d -> %d", before, after)
+	}
+}
+
+// countingRetoucher 记录调用次数、输出纯色图片的修饰实现
+type countingRetoucher struct {
+	calls int
+	color color.RGBA
+}
+
+func (r *countingRetoucher) Retouch(img image.Image, opts RetouchOptions) (image.Image, error) {
+	r.calls++
+	if !opts.RedEye {
+		return nil, errors.New("unexpected options")
+	}
+	return solidImage(10, 10, r.color), nil
+}
+
+// TestRetoucherHook 测试自定义修饰实现的调用、缓存失效和首字母头像的跳过
+func TestRetoucherHook(t *testing.T) {
+	green := &countingRetoucher{color: color.RGBA{0, 255, 0, 255}}
+	combiner := NewImageCombiner(20, 10)
+	combiner.Retoucher = green
+	photo := &ImageElement{image: solidImage(10, 10, color.RGBA{255, 0, 0, 255}), ZoomMode: Origin, Alpha: 255, Retouch: &RetouchOptions{RedEye: true}}
+	combiner.AddElement(photo)
+	combiner.AddAvatarElement("", "张三", 10, 0, 10).Retouch = &RetouchOptions{RedEye: true}
+
+	for i := 0; i < 2; i++ {
+		if _, err := combiner.Combine(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if green.calls != 1 {
+		t.Errorf("修饰应只对真实图片执行一次，实际 %d 次", green.calls)
+	}
+	if photo.retouched != nil {
+		t.Error("修饰结果不应写入元素")
+	}
+
+	// 修改选项或替换Retoucher后重新修饰
+	photo.Retouch = &RetouchOptions{RedEye: true, SmoothSkin: 0.5}
+	combiner.Combine()
+	if green.calls != 2 {
+		t.Errorf("选项变化后应重新修饰，实际调用 %d 次", green.calls)
+	}
+	combiner.Retoucher = &countingRetoucher{color: color.RGBA{0, 0, 255, 255}}
+	img, _ := combiner.Combine()
+	if c := rgb(img.At(5, 5)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("替换Retoucher后应使用新的修饰结果，实际 %v", c)
+	}
+
+	// 函数形式的Retoucher无法比较，只在同一次逐帧渲染内复用结果
+	calls := 0
+	combiner.Retoucher = RetoucherFunc(func(img image.Image, opts RetouchOptions) (image.Image, error) {
+		calls++
+		return img, nil
+	})
+	combiner.Combine()
+	combiner.Combine()
+	if calls != 2 {
+		t.Errorf("函数形式的Retoucher应每次合成重新修饰，实际 %d 次", calls)
+	}
+	calls = 0
+	if err := combiner.WriteFrames(io.Discard, 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("逐帧渲染时应只修饰一次，实际 %d 次", calls)
+	}
+}
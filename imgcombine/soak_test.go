@@ -0,0 +1,85 @@
+package imgcombine
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// soakEnv 设置后运行浸泡测试，值为渲染次数，如 IMGCOMBINE_SOAK=100000 go test -run TestSoak -timeout 0
+const soakEnv = "IMGCOMBINE_SOAK"
+
+// TestSoak 长时间重复渲染包含图片、半透明文本、渐变和缓存图层的模板，
+// 检查全部缓存设置上限并释放合成器后堆内存保持平稳
+func TestSoak(t *testing.T) {
+	iterations, _ := strconv.Atoi(os.Getenv(soakEnv))
+	if iterations <= 0 {
+		t.Skipf("设置 %s 为渲染次数以运行浸泡测试", soakEnv)
+	}
+
+	imagePath := filepath.Join(t.TempDir(), "photo.png")
+	photo := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for i := range photo.Pix {
+		photo.Pix[i] = uint8(i * 7)
+	}
+	f, err := os.Create(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, photo)
+	f.Close()
+
+	SetFontFileCacheLimit(4)
+	defer SetFontFileCacheLimit(0)
+	cache := NewLayerCache()
+	cache.SetMaxBytes(8 << 20)
+
+	render := func(i int) {
+		combiner := NewImageCombiner(600, 400)
+		combiner.OutputFormat = PNG
+		combiner.LayerCache = cache
+		combiner.FontPaths = []string{"../Alibaba-PuHuiTi-Medium.ttf"}
+		if _, err := combiner.AddImageElement(imagePath, 0, 0, Origin); err != nil {
+			t.Fatal(err)
+		}
+		combiner.AddGaugeElement(float64(i%500), 0, 500, 450, 20, 60)
+		title := combiner.AddTextElement("浸泡测试 "+strconv.Itoa(i), 32, 20, 360)
+		title.Transparency = 95
+		title.Gradient = NewLinearGradient(0, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255})
+		if _, err := combiner.ToBytes(); err != nil {
+			t.Fatal(err)
+		}
+		combiner.Release()
+	}
+
+	heap := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+
+	// 预热后记录基线，缓存填满前的增长不计入
+	warmup := min(iterations/10, 1000)
+	for i := 0; i < warmup; i++ {
+		render(i)
+	}
+	baseline := heap()
+	peak := baseline
+	for i := warmup; i < iterations; i++ {
+		render(i)
+		if i%max(iterations/20, 1) == 0 {
+			peak = max(peak, heap())
+		}
+	}
+	end := heap()
+	t.Logf("渲染 %d 次，基线 %d KB，峰值 %d KB，结束 %d KB", iterations, baseline>>10, peak>>10, end>>10)
+	if end > baseline+baseline/2+16<<20 {
+		t.Errorf("堆内存持续增长：基线 %d KB，结束 %d KB", baseline>>10, end>>10)
+	}
+}
@@ -0,0 +1,79 @@
+package imgcombine
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestSVGImage 以模拟的rsvg-convert测试SVG按目标尺寸渲染
+func TestSVGImage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟的rsvg-convert为shell脚本")
+	}
+	dir := t.TempDir()
+	writePNG := func(name string, w, h int, c color.Color) string {
+		p := filepath.Join(dir, name)
+		f, err := os.Create(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		png.Encode(f, solidImage(w, h, c))
+		f.Close()
+		return p
+	}
+	// 自身尺寸渲染为红色，按目标尺寸渲染为蓝色，便于区分是否重新渲染
+	natural := writePNG("natural.png", 10, 10, color.RGBA{255, 0, 0, 255})
+	sized := writePNG("sized.png", 40, 20, color.RGBA{0, 0, 255, 255})
+	svgPath := filepath.Join(dir, "logo.svg")
+	os.WriteFile(svgPath, []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), 0644)
+
+	argsPath := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsPath + "\ncat > /dev/null\n" +
+		"if [ \"$1\" = -w ]; then cat " + sized + "; else cat " + natural + "; fi\n"
+	tool := filepath.Join(dir, "rsvg-convert")
+	if err := os.WriteFile(tool, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { RsvgConvertPath = path }(RsvgConvertPath)
+	RsvgConvertPath = tool
+
+	if !isSVG("https://example.com/logo.SVG?v=2") || isSVG("logo.png") {
+		t.Error("SVG路径判断错误")
+	}
+
+	combiner := NewImageCombiner(60, 30)
+	element, err := combiner.AddImageElement(svgPath, 0, 0, WidthHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := element.image.Bounds(); b.Dx() != 10 {
+		t.Errorf("加载时应按自身尺寸渲染: %v", b)
+	}
+	element.Width, element.Height = 40, 20
+
+	img, _ := combiner.Combine()
+	if args, _ := os.ReadFile(argsPath); string(args) != "-w 40 -h 20 -f png -\n" {
+		t.Errorf("rsvg-convert参数错误: %q", args)
+	}
+	if c := rgb(img.At(20, 10)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("应绘制按目标尺寸渲染的结果，实际 %v", c)
+	}
+
+	// 相同尺寸再次合成时使用缓存
+	os.Remove(argsPath)
+	combiner.Combine()
+	if _, err := os.Stat(argsPath); err == nil {
+		t.Error("相同尺寸不应重复渲染")
+	}
+
+	// 翻转后只能缩放自身尺寸的渲染结果
+	element.FlipH = true
+	img, _ = combiner.Combine()
+	if c := rgb(img.At(20, 10)); c != [3]uint32{255, 0, 0} {
+		t.Errorf("翻转时应缩放自身尺寸的渲染结果，实际 %v", c)
+	}
+}
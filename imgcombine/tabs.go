@@ -0,0 +1,65 @@
+package imgcombine
+
+import (
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+// defaultTabWidth 未设置TabStops或超出最后一个制表位后，制表位间隔为字号的4倍
+const defaultTabWidth = 4
+
+// nextTabStop 返回位于offset之后的下一个制表位，offset与制表位均相对于行首
+func (te *TextElement) nextTabStop(offset float64) float64 {
+	for _, stop := range te.TabStops {
+		if stop > offset {
+			return stop
+		}
+	}
+	interval := te.FontSize * defaultTabWidth
+	if interval <= 0 {
+		return offset
+	}
+	last := 0.0
+	if n := len(te.TabStops); n > 0 {
+		last = te.TabStops[n-1]
+	}
+	if offset < last {
+		return last
+	}
+	return last + (float64(int((offset-last)/interval))+1)*interval
+}
+
+// lineWidth 测量单行文本宽度，制表符按制表位计算
+func (te *TextElement) lineWidth(g *gg.Context, line string) float64 {
+	if !strings.Contains(line, "\t") {
+		width, _ := g.MeasureString(line)
+		return width
+	}
+	offset := 0.0
+	for i, segment := range strings.Split(line, "\t") {
+		if i > 0 {
+			offset = te.nextTabStop(offset)
+		}
+		width, _ := g.MeasureString(segment)
+		offset += width
+	}
+	return offset
+}
+
+// drawLine 在(x, y)处绘制单行文本，制表符后的内容从下一个制表位开始绘制
+func (te *TextElement) drawLine(g *gg.Context, line string, x, y float64, style synthStyle) {
+	if !strings.Contains(line, "\t") {
+		style.drawString(g, line, x, y, 0, 0)
+		return
+	}
+	offset := 0.0
+	for i, segment := range strings.Split(line, "\t") {
+		if i > 0 {
+			offset = te.nextTabStop(offset)
+		}
+		style.drawString(g, segment, x+offset, y, 0, 0)
+		width, _ := g.MeasureString(segment)
+		offset += width
+	}
+}
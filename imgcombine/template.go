@@ -0,0 +1,81 @@
+package imgcombine
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Render 以data填充文本中的text/template占位符（如"{{.UserName}}"），返回绑定数据后的合成器
+// 原合成器保持不变，可作为模板反复为不同记录渲染；
+// 文本元素的Text、富文本片段和圆弧文本均参与替换，data中缺少的字段返回错误；入场动画随元素一起复制
+// 绘制时会缓存状态的元素被复制，修饰、抠图结果和随机数生成器各自独立，多个绑定结果可并发渲染；LayerCache仍然共享
+func (ic *ImageCombiner) Render(data any) (*ImageCombiner, error) {
+	bound := *ic
+	bound.processed = nil
+	bound.rng = nil
+	if ic.seeded {
+		bound.rng = rand.New(rand.NewSource(ic.seed))
+	}
+	bound.elements = make([]CombineElement, len(ic.elements))
+	for i, element := range ic.elements {
+		var err error
+		switch e := element.(type) {
+		case *TextElement:
+			text := *e
+			if text.Text, err = executeText(e.Text, data); err != nil {
+				break
+			}
+			if e.Spans != nil {
+				text.Spans = make([]TextSpan, len(e.Spans))
+			}
+			for j, span := range e.Spans {
+				if span.Text, err = executeText(span.Text, data); err != nil {
+					break
+				}
+				text.Spans[j] = span
+			}
+			element = &text
+		case *ArcTextElement:
+			arc := *e
+			arc.Text, err = executeText(e.Text, data)
+			element = &arc
+		case *ImageElement:
+			// SVG按绘制尺寸的栅格化结果缓存在元素上
+			copied := *e
+			element = &copied
+		}
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %v", i, err)
+		}
+		bound.elements[i] = element
+	}
+	// 动画指向复制后的元素，否则绑定后的元素不会播放动画
+	bound.animations = make([]*Animation, len(ic.animations))
+	for i, a := range ic.animations {
+		animation := *a
+		if j := slices.Index(ic.elements, a.element); j >= 0 {
+			animation.element = bound.elements[j]
+		}
+		bound.animations[i] = &animation
+	}
+	return &bound, nil
+}
+
+// executeText 执行文本中的模板，不含占位符时原样返回
+func executeText(text string, data any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("text").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
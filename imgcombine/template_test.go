@@ -0,0 +1,93 @@
+package imgcombine
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"sync"
+	"testing"
+)
+
+// TestRender 测试同一模板为不同记录绑定数据
+func TestRender(t *testing.T) {
+	combiner := NewImageCombiner(300, 100)
+	title := combiner.AddTextElement("你好，{{.UserName}}", 20, 10, 30)
+	price := combiner.AddTextElement("", 20, 10, 60)
+	price.Spans = []TextSpan{{Text: "价格："}, {Text: "￥{{printf \"%.2f\" .Price}}"}}
+	combiner.AddRectangleElement(0, 0, 10, 10)
+	combiner.Animate(title, FadeIn, 0, 5)
+
+	type record struct {
+		UserName string
+		Price    float64
+	}
+	for _, r := range []record{{"张三", 99}, {"李四", 1290.5}} {
+		bound, err := combiner.Render(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := bound.elements[0].(*TextElement)
+		if want := "你好，" + r.UserName; text.Text != want {
+			t.Errorf("标题 %q，期望 %q", text.Text, want)
+		}
+		if got := bound.elements[1].(*TextElement).Spans[1].Text; got != fmt.Sprintf("￥%.2f", r.Price) {
+			t.Errorf("价格片段 %q", got)
+		}
+		if a := bound.animation(text); a == nil || a.Preset != FadeIn {
+			t.Error("绑定后的文本应保留入场动画")
+		}
+		if _, err := bound.Combine(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if title.Text != "你好，{{.UserName}}" || price.Spans[1].Text != "￥{{printf \"%.2f\" .Price}}" {
+		t.Error("Render不应修改模板")
+	}
+	if combiner.animation(title) == nil {
+		t.Error("Render不应修改模板的动画")
+	}
+
+	if _, err := combiner.Render(map[string]any{"UserName": "王五"}); err == nil {
+		t.Error("缺少字段时应返回错误")
+	}
+}
+
+// TestRenderIndependent 测试绑定结果互相独立，可并发渲染
+func TestRenderIndependent(t *testing.T) {
+	combiner := NewImageCombiner(60, 40)
+	combiner.OutputFormat = PNG
+	combiner.SetSeed(3)
+	avatar := combiner.AddImageElementFromImage(image.NewRGBA(image.Rect(0, 0, 20, 20)), 0, 0, WidthHeight)
+	avatar.Retouch = &RetouchOptions{RedEye: true}
+	combiner.AddConfettiElement(ParticleConfetti, 10, 20, 0, 40, 40)
+	combiner.AddTextElement("{{.}}", 12, 0, 30)
+	if _, err := combiner.Combine(); err != nil {
+		t.Fatal(err)
+	}
+
+	bounds := make([]*ImageCombiner, 2)
+	want := make([][]byte, len(bounds))
+	for i := range bounds {
+		bound, err := combiner.Render(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bound.elements[0] == avatar || bound.processed == combiner.processed {
+			t.Error("绑定结果不应与模板共享图片元素和处理结果缓存")
+		}
+		bounds[i] = bound
+		want[i], _ = bound.ToBytes()
+	}
+
+	var wg sync.WaitGroup
+	for i, bound := range bounds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, _ := bound.ToBytes(); !bytes.Equal(got, want[i]) {
+				t.Errorf("绑定结果%d并发渲染与单独渲染不一致", i)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,239 @@
+package imgcombine
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// TextSpan 富文本片段，同一文本元素中的多个片段连续排版、共同参与换行
+// 未设置的样式继承所属文本元素
+type TextSpan struct {
+	Text          string      // 片段文本
+	Color         color.Color // 文本颜色，为nil时使用元素颜色
+	FontSize      float64     // 字体大小，为0时使用元素字号
+	FontFamily    string      // 字体注册表中的字体名称，可用于切换粗体等字重，为空时使用元素字体
+	Bold          bool        // 粗体，元素设置了Bold时同样生效
+	Italic        bool        // 斜体，元素设置了Italic时同样生效
+	StrikeThrough bool        // 是否显示删除线
+	Underline     bool        // 是否显示下划线
+}
+
+// plainText 返回元素显示的纯文本：文本元素的Text或拼接后的富文本片段、圆弧文本，其他元素返回空字符串
+// 审核、无障碍描述和对比度检查均以此获取元素文本
+func plainText(element CombineElement) string {
+	switch e := element.(type) {
+	case *TextElement:
+		if len(e.Spans) == 0 {
+			return e.Text
+		}
+		var b strings.Builder
+		for _, span := range e.Spans {
+			b.WriteString(span.Text)
+		}
+		return b.String()
+	case *ArcTextElement:
+		return e.Text
+	}
+	return ""
+}
+
+// spanRun 一行中属于同一片段的连续文本
+type spanRun struct {
+	span int    // 片段下标
+	text []rune // 文本内容
+}
+
+// spanLine 富文本排版后的一行
+type spanLine struct {
+	runs  []spanRun
+	width float64
+}
+
+// spanFaces 为每个片段创建字体，并返回各片段需要模拟的样式
+func (te *TextElement) spanFaces() ([]font.Face, []synthStyle) {
+	faces := make([]font.Face, len(te.Spans))
+	styles := make([]synthStyle, len(te.Spans))
+	for i := range te.Spans {
+		faces[i], styles[i] = te.spanFace(i)
+		if faces[i] == nil {
+			// 无可用字体时使用gg默认字体
+			faces[i] = basicfont.Face7x13
+		}
+	}
+	return faces, styles
+}
+
+// spanSize 返回片段的字号
+func (te *TextElement) spanSize(i int) float64 {
+	if size := te.Spans[i].FontSize; size > 0 {
+		return size
+	}
+	return te.FontSize
+}
+
+// spanColor 返回片段的颜色
+func (te *TextElement) spanColor(i int) color.Color {
+	if c := te.Spans[i].Color; c != nil {
+		return c
+	}
+	return te.textColor()
+}
+
+// runsWidth 计算一组文本段的总宽度
+func runsWidth(runs []spanRun, faces []font.Face) float64 {
+	width := 0.0
+	for _, run := range runs {
+		width += float64(font.MeasureString(faces[run.span], string(run.text))) / 64
+	}
+	return width
+}
+
+// appendRune 返回追加字符后的文本段，不修改原切片
+func appendRune(runs []spanRun, span int, r rune) []spanRun {
+	result := make([]spanRun, len(runs), len(runs)+1)
+	copy(result, runs)
+	if n := len(result); n > 0 && result[n-1].span == span {
+		result[n-1].text = append(append([]rune{}, result[n-1].text...), r)
+		return result
+	}
+	return append(result, spanRun{span: span, text: []rune{r}})
+}
+
+// runeCount 统计文本段中的字符数
+func runeCount(runs []spanRun) int {
+	n := 0
+	for _, run := range runs {
+		n += len(run.text)
+	}
+	return n
+}
+
+// lastRune 返回文本段中的最后一个字符
+func lastRune(runs []spanRun) (span int, r rune) {
+	run := runs[len(runs)-1]
+	return run.span, run.text[len(run.text)-1]
+}
+
+// dropLastRune 返回删除最后一个字符后的文本段
+func dropLastRune(runs []spanRun) []spanRun {
+	result := append([]spanRun{}, runs...)
+	last := &result[len(result)-1]
+	last.text = last.text[:len(last.text)-1]
+	if len(last.text) == 0 {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// layoutSpans 将富文本片段排版为行，换行、避头尾、最大行数和省略后缀规则与纯文本一致
+func (te *TextElement) layoutSpans() []spanLine {
+	faces, _ := te.spanFaces()
+
+	var lines [][]spanRun
+	var current []spanRun
+	hardBreak := false
+	for i, span := range te.Spans {
+		for _, r := range normalizeNewlines(span.Text) {
+			// 换行符强制换行，连续换行产生空行
+			hardBreak = r == '\n'
+			if hardBreak {
+				lines = append(lines, current)
+				current = nil
+				continue
+			}
+
+			test := appendRune(current, i, r)
+			if te.MaxLineWidth <= 0 || runsWidth(test, faces) <= float64(te.MaxLineWidth) || len(current) == 0 {
+				current = test
+				continue
+			}
+
+			next := []spanRun{{span: i, text: []rune{r}}}
+			// 避头尾规则：将上一行末尾字符移到新行
+			for runeCount(current) > 1 {
+				lastSpan, last := lastRune(current)
+				if !isLineStartProhibited(next[0].text[0]) && !isLineEndProhibited(last) {
+					break
+				}
+				if next[0].span == lastSpan {
+					next[0].text = append([]rune{last}, next[0].text...)
+				} else {
+					next = append([]spanRun{{span: lastSpan, text: []rune{last}}}, next...)
+				}
+				current = dropLastRune(current)
+			}
+			lines = append(lines, current)
+			current = next
+		}
+	}
+	if len(current) > 0 || hardBreak {
+		lines = append(lines, current)
+	}
+
+	// 应用最大行数和最大高度限制，省略后缀使用最后一个字符所在片段的样式
+	if limit := te.maxLines(spanExtents(faces)); limit > 0 && len(lines) > limit {
+		lines = lines[:limit]
+		if last := lines[len(lines)-1]; te.Ellipsis != "" && len(last) > 0 {
+			span, _ := lastRune(last)
+			for runeCount(last) > 0 {
+				withEllipsis := append(append([]spanRun{}, last...), spanRun{span: span, text: []rune(te.Ellipsis)})
+				if te.MaxLineWidth <= 0 || runsWidth(withEllipsis, faces) <= float64(te.MaxLineWidth) {
+					break
+				}
+				last = dropLastRune(last)
+			}
+			lines[len(lines)-1] = appendRunes(last, span, []rune(te.Ellipsis))
+		}
+	}
+
+	result := make([]spanLine, len(lines))
+	for i, runs := range lines {
+		result[i] = spanLine{runs: runs, width: runsWidth(runs, faces)}
+	}
+	return result
+}
+
+// appendRunes 返回追加一段文本后的文本段
+func appendRunes(runs []spanRun, span int, text []rune) []spanRun {
+	for _, r := range text {
+		runs = appendRune(runs, span, r)
+	}
+	return runs
+}
+
+// drawSpans 绘制富文本，各片段按自身样式绘制，行高默认为最大字号的1.5倍
+func (te *TextElement) drawSpans(g *gg.Context) {
+	lineHeight := te.lineHeight()
+	faces, styles := te.spanFaces()
+	for i, line := range te.layoutSpans() {
+		x := te.lineX(line.width)
+		y := float64(te.Y) + float64(i)*lineHeight
+		for _, run := range line.runs {
+			span := te.Spans[run.span]
+			size := te.spanSize(run.span)
+			text := string(run.text)
+			width := float64(font.MeasureString(faces[run.span], text)) / 64
+
+			g.SetFontFace(faces[run.span])
+			g.SetColor(te.spanColor(run.span))
+			styles[run.span].drawString(g, text, x, y, 0, 0)
+
+			g.SetLineWidth(size / 20)
+			if span.StrikeThrough || te.StrikeThrough {
+				strikeY := y - size*0.4
+				g.DrawLine(x, strikeY, x+width, strikeY)
+				g.Stroke()
+			}
+			if span.Underline {
+				underlineY := y + size*0.15
+				g.DrawLine(x, underlineY, x+width, underlineY)
+				g.Stroke()
+			}
+			x += width
+		}
+	}
+}
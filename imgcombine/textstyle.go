@@ -0,0 +1,102 @@
+package imgcombine
+
+import (
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+)
+
+// 字体变体后缀：以"名称-Bold"、"名称-Italic"、"名称-BoldItalic"注册的字体
+// 会在设置Bold/Italic时替代FontFamily，未注册时模拟对应样式
+const (
+	boldSuffix       = "-Bold"
+	italicSuffix     = "-Italic"
+	boldItalicSuffix = "-BoldItalic"
+)
+
+// italicShear 模拟斜体的水平错切系数
+const italicShear = 0.2
+
+// synthStyle 字体缺少对应变体时需要模拟的样式
+type synthStyle struct {
+	bold, italic bool
+	size         float64
+}
+
+// styledFamily 查找family的粗体/斜体变体，返回变体名称和仍需模拟的样式
+// 未找到任何变体时返回空名称，表示沿用原字体
+func (te *TextElement) styledFamily(family string, bold, italic bool, size float64) (string, synthStyle) {
+	style := synthStyle{bold: bold, italic: italic, size: size}
+	if family == "" || (!bold && !italic) {
+		return "", style
+	}
+
+	registry := te.registry()
+	candidates := []struct {
+		suffix       string
+		bold, italic bool
+	}{
+		{boldItalicSuffix, true, true},
+		{boldSuffix, true, false},
+		{italicSuffix, false, true},
+	}
+	for _, c := range candidates {
+		if (c.bold && !bold) || (c.italic && !italic) {
+			continue
+		}
+		if _, ok := registry.Font(family + c.suffix); ok {
+			style.bold = bold && !c.bold
+			style.italic = italic && !c.italic
+			return family + c.suffix, style
+		}
+	}
+	return "", style
+}
+
+// face 创建文本元素的字体，设置了Bold/Italic时优先使用已注册的变体
+func (te *TextElement) face() (font.Face, synthStyle) {
+	family, style := te.styledFamily(te.fontFamily(), te.Bold, te.Italic, te.FontSize)
+	return te.newFace(te.FontSize, family), style
+}
+
+// spanFace 创建片段的字体，片段未设置字体时以元素字体查找变体
+func (te *TextElement) spanFace(i int) (font.Face, synthStyle) {
+	span := te.Spans[i]
+	base := span.FontFamily
+	if base == "" {
+		base = te.fontFamily()
+	}
+	family, style := te.styledFamily(base, span.Bold || te.Bold, span.Italic || te.Italic, te.spanSize(i))
+	if family == "" {
+		family = span.FontFamily
+	}
+	return te.newFace(te.spanSize(i), family), style
+}
+
+// drawString 按模拟样式绘制文本，锚点含义与gg.DrawStringAnchored相同
+// 粗体通过错位重复绘制加粗笔画，斜体以基线为轴水平错切
+func (st synthStyle) drawString(g *gg.Context, s string, x, y, ax, ay float64) {
+	if st.italic {
+		g.Push()
+		defer g.Pop()
+		g.ShearAbout(-italicShear, 0, x, y)
+	}
+
+	g.DrawStringAnchored(s, x, y, ax, ay)
+	if st.bold {
+		d := max(st.size/40, 0.5)
+		g.DrawStringAnchored(s, x+d, y, ax, ay)
+		g.DrawStringAnchored(s, x, y-d, ax, ay)
+		g.DrawStringAnchored(s, x+d, y-d, ax, ay)
+	}
+}
+
+// drawTranslucent 按Transparency绘制半透明文本：先在图层上不透明绘制，再整体应用透明度合成
+// 避免模拟粗体的重复绘制和删除线与文字重叠处透明度叠加
+func (te *TextElement) drawTranslucent(g *gg.Context, canvasWidth int) {
+	opaque := *te
+	opaque.Transparency = 0
+	scratch := getLayer(g.Width(), g.Height())
+	defer putLayer(scratch)
+	opaque.Draw(gg.NewContextForRGBA(scratch), canvasWidth)
+	g.DrawImage(CurrentAccelerator().ApplyAlpha(scratch, 255-te.Transparency), 0, 0)
+}
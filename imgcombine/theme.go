@@ -0,0 +1,237 @@
+package imgcombine
+
+import (
+	"image/color"
+	"maps"
+	"reflect"
+)
+
+// ColorScheme 配色模式，决定ThemeColor按主题的浅色还是深色变体解析
+type ColorScheme string
+
+const (
+	SchemeLight ColorScheme = "light" // 浅色（默认）
+	SchemeDark  ColorScheme = "dark"  // 深色，使用Theme.Dark
+)
+
+// 标准颜色令牌，主题调色板未定义时浅色模式为黑字白底，深色模式为白字黑底
+// 设置了主题时画布以ColorBg填充
+const (
+	ColorFg     ThemeColor = "fg"     // 前景（文本）颜色
+	ColorBg     ThemeColor = "bg"     // 背景颜色
+	ColorAccent ThemeColor = "accent" // 强调色
+)
+
+// Theme 全局样式主题，设置在合成器上统一品牌风格
+// 元素未设置的字体、文本颜色、行高和圆角在渲染时取主题的默认值，颜色可通过ThemeColor按名称引用调色板，
+// 因此修改主题无需逐个编辑元素
+type Theme struct {
+	FontFamily   string                 // 默认字体（字体注册表名称）
+	TextColor    color.Color            // 默认文本颜色
+	LineHeight   float64                // 默认行高倍数（相对字号），0表示使用1.5倍
+	CornerRadius int                    // 图片和矩形的默认圆角半径
+	Palette      map[string]color.Color // 命名颜色，元素通过ThemeColor引用
+	Dark         *Theme                 // 深色模式变体，仅使用其调色板，未定义的颜色沿用浅色调色板
+}
+
+// ThemeColor 引用主题调色板中的命名颜色，在合成时替换为对应颜色
+// 合成器未设置主题或调色板中没有该名称时按黑色绘制，JSON中编码为"@名称"
+type ThemeColor string
+
+// RGBA 实现color.Color接口，返回未解析时使用的黑色
+func (ThemeColor) RGBA() (r, g, b, a uint32) {
+	return 0, 0, 0, 0xffff
+}
+
+// Color 返回调色板中的命名颜色
+func (t *Theme) Color(name string) (color.Color, bool) {
+	if t == nil {
+		return nil, false
+	}
+	c, ok := t.Palette[name]
+	return c, ok
+}
+
+// forScheme 返回按配色模式合并后的调色板主题，并补全标准颜色令牌的默认值
+func (t *Theme) forScheme(scheme ColorScheme) *Theme {
+	if t == nil {
+		return nil
+	}
+	merged := *t
+	merged.Palette = maps.Clone(t.Palette)
+	if merged.Palette == nil {
+		merged.Palette = make(map[string]color.Color)
+	}
+	fg, bg := color.Color(color.Black), color.Color(color.White)
+	if scheme == SchemeDark {
+		fg, bg = bg, fg
+		if t.Dark != nil {
+			maps.Copy(merged.Palette, t.Dark.Palette)
+		}
+	}
+	for name, c := range map[ThemeColor]color.Color{ColorFg: fg, ColorBg: bg} {
+		if _, ok := merged.Palette[string(name)]; !ok {
+			merged.Palette[string(name)] = c
+		}
+	}
+	return &merged
+}
+
+// activeTheme 返回按合成器配色模式解析颜色的主题，未设置主题时返回nil
+func (ic *ImageCombiner) activeTheme() *Theme {
+	return ic.Theme.forScheme(ic.ColorScheme)
+}
+
+// background 返回画布背景色：设置了主题时为ColorBg，否则为白色
+func (t *Theme) background() color.Color {
+	if c, ok := t.Color(string(ColorBg)); ok {
+		return c
+	}
+	return color.White
+}
+
+// ToBytesSchemes 分别以浅色和深色模式合成同一模板，返回两种配色的图片数据
+func (ic *ImageCombiner) ToBytesSchemes() (light, dark []byte, err error) {
+	scheme := ic.ColorScheme
+	defer func() { ic.ColorScheme = scheme }()
+
+	ic.ColorScheme = SchemeLight
+	if light, err = ic.ToBytes(); err != nil {
+		return nil, nil, err
+	}
+	ic.ColorScheme = SchemeDark
+	if dark, err = ic.ToBytes(); err != nil {
+		return nil, nil, err
+	}
+	return light, dark, nil
+}
+
+// withDefaults 返回以主题默认值补全零值字段后的元素副本，无需补全时返回原元素
+// 文本颜色和圆角在合成时补全；字体和行高同样影响测量，由文本元素通过所属合成器的主题读取
+func (t *Theme) withDefaults(element CombineElement) CombineElement {
+	if t == nil {
+		return element
+	}
+	switch e := element.(type) {
+	case *TextElement:
+		if e.Color == nil && t.TextColor != nil {
+			copied := *e
+			copied.Color = t.TextColor
+			return &copied
+		}
+	case *RectangleElement:
+		if e.RoundCorner == 0 && t.CornerRadius > 0 {
+			copied := *e
+			copied.RoundCorner = t.CornerRadius
+			return &copied
+		}
+	case *ImageElement:
+		if e.RoundCorner == 0 && t.CornerRadius > 0 {
+			copied := *e
+			copied.RoundCorner = t.CornerRadius
+			return &copied
+		}
+	}
+	return element
+}
+
+// theme 返回文本元素所属合成器的主题，未设置时返回nil
+func (te *TextElement) theme() *Theme {
+	if te.combiner == nil {
+		return nil
+	}
+	return te.combiner.Theme
+}
+
+// fontFamily 返回文本元素的字体名称，未设置时使用主题的默认字体
+func (te *TextElement) fontFamily() string {
+	if te.FontFamily == "" {
+		if t := te.theme(); t != nil {
+			return t.FontFamily
+		}
+	}
+	return te.FontFamily
+}
+
+// textColor 返回文本颜色，未设置时为黑色
+func (te *TextElement) textColor() color.Color {
+	if te.Color != nil {
+		return te.Color
+	}
+	return color.Black
+}
+
+// resolve 返回将ThemeColor替换为调色板颜色后的元素副本，元素不含ThemeColor时返回原元素
+func (t *Theme) resolve(element CombineElement) CombineElement {
+	if t == nil {
+		return element
+	}
+	if v, ok := t.resolveValue(reflect.ValueOf(element)); ok {
+		return v.Interface().(CombineElement)
+	}
+	return element
+}
+
+// resolveValue 递归替换导出字段中的ThemeColor，仅复制包含ThemeColor的部分，未替换时返回false
+func (t *Theme) resolveValue(v reflect.Value) (reflect.Value, bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, false
+		}
+		name, ok := v.Interface().(ThemeColor)
+		if !ok || v.Type() != colorType {
+			return v, false
+		}
+		c, ok := t.Color(string(name))
+		if !ok {
+			return v, false
+		}
+		out := reflect.New(colorType).Elem()
+		out.Set(reflect.ValueOf(c))
+		return out, true
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, false
+		}
+		elem, ok := t.resolveValue(v.Elem())
+		if !ok {
+			return v, false
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(elem)
+		return out, true
+	case reflect.Struct:
+		var out reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			field, ok := t.resolveValue(v.Field(i))
+			if !ok {
+				continue
+			}
+			if !out.IsValid() {
+				out = reflect.New(v.Type()).Elem()
+				out.Set(v)
+			}
+			out.Field(i).Set(field)
+		}
+		return out, out.IsValid()
+	case reflect.Slice:
+		var out reflect.Value
+		for i := 0; i < v.Len(); i++ {
+			item, ok := t.resolveValue(v.Index(i))
+			if !ok {
+				continue
+			}
+			if !out.IsValid() {
+				out = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+				reflect.Copy(out, v)
+			}
+			out.Index(i).Set(item)
+		}
+		return out, out.IsValid()
+	}
+	return v, false
+}
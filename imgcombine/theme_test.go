@@ -0,0 +1,175 @@
+package imgcombine
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestThemeDefaults 测试元素未设置的字段在渲染时取主题默认值，主题可在添加元素后设置或修改
+func TestThemeDefaults(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	text := combiner.AddTextElement("HH", 40, 0, 40)
+	text.FontBytes = goregular.TTF
+	rect := combiner.AddRectangleElement(50, 50, 50, 50)
+	rect.Color = color.RGBA{0, 0, 255, 255}
+
+	combiner.Theme = &Theme{
+		FontFamily:   "brand",
+		TextColor:    color.RGBA{255, 0, 0, 255},
+		LineHeight:   1.2,
+		CornerRadius: 20,
+	}
+	if text.fontFamily() != "brand" || text.lineHeight() != 48 {
+		t.Errorf("文本默认值错误: %q %.1f", text.fontFamily(), text.lineHeight())
+	}
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := rgb(img.At(51, 51)); c != [3]uint32{255, 255, 255} {
+		t.Errorf("矩形应使用主题圆角，角落颜色 %v", c)
+	}
+	if c := rgb(img.At(75, 75)); c != [3]uint32{0, 0, 255} {
+		t.Errorf("矩形颜色错误: %v", c)
+	}
+	if !hasColor(img, color.RGBA{255, 0, 0, 255}) {
+		t.Error("文本应使用主题颜色")
+	}
+	if text.Color != nil || rect.RoundCorner != 0 {
+		t.Error("主题默认值不应写入元素")
+	}
+
+	// 元素显式设置的值优先于主题
+	text.Color = color.RGBA{0, 255, 0, 255}
+	text.LineHeight = 30
+	if img, _ = combiner.Combine(); hasColor(img, color.RGBA{255, 0, 0, 255}) || text.lineHeight() != 30 {
+		t.Error("元素设置的颜色和行高应优先于主题")
+	}
+}
+
+// hasColor 判断图片中是否存在指定颜色的像素
+func hasColor(img image.Image, c color.RGBA) bool {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestThemeColor 测试ThemeColor在合成时按调色板解析，且不修改元素本身
+func TestThemeColor(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	combiner.Theme = &Theme{Palette: map[string]color.Color{"accent": color.RGBA{255, 0, 0, 255}}}
+	rect := combiner.AddRectangleElement(0, 0, 100, 100)
+	rect.Color = ThemeColor("accent")
+	text := combiner.AddTextElement("A", 20, 10, 30)
+	text.Spans = []TextSpan{{Text: "A", Color: ThemeColor("accent")}}
+
+	img, _ := combiner.Combine()
+	if c := color.RGBAModel.Convert(img.At(90, 90)); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("应使用调色板颜色，实际 %v", c)
+	}
+	if rect.Color != ThemeColor("accent") || text.Spans[0].Color != ThemeColor("accent") {
+		t.Error("解析主题颜色不应修改元素")
+	}
+
+	// 修改调色板后重新合成即生效
+	combiner.Theme.Palette["accent"] = color.RGBA{0, 0, 255, 255}
+	img, _ = combiner.Combine()
+	if c := color.RGBAModel.Convert(img.At(90, 90)); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("修改调色板后应使用新颜色，实际 %v", c)
+	}
+}
+
+// TestThemeJSON 测试主题和主题颜色的序列化
+func TestThemeJSON(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	combiner.Theme = &Theme{
+		TextColor: color.RGBA{1, 2, 3, 255},
+		Palette:   map[string]color.Color{"accent": color.RGBA{255, 0, 0, 255}},
+	}
+	combiner.AddRectangleElement(0, 0, 10, 10).Color = ThemeColor("accent")
+
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := &ImageCombiner{}
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := restored.Theme.Color("accent"); !ok || color.RGBAModel.Convert(c) != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("调色板未恢复: %v", restored.Theme.Palette)
+	}
+	if rect := restored.elements[0].(*RectangleElement); rect.Color != ThemeColor("accent") {
+		t.Errorf("主题颜色引用未恢复: %v", rect.Color)
+	}
+}
+
+// TestThemeDarkMode 测试同一模板按浅色和深色模式解析颜色令牌
+func TestThemeDarkMode(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	combiner.OutputFormat = PNG
+	combiner.Theme = &Theme{
+		TextColor: ColorFg,
+		Palette:   map[string]color.Color{"accent": color.RGBA{255, 0, 0, 255}},
+		Dark:      &Theme{Palette: map[string]color.Color{"bg": color.RGBA{20, 20, 30, 255}}},
+	}
+	combiner.AddRectangleElement(60, 60, 40, 40).Color = ColorAccent
+	text := combiner.AddTextElement("HH", 40, 0, 40)
+	text.FontBytes = goregular.TTF
+
+	light, dark, err := combiner.ToBytesSchemes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if combiner.ColorScheme != "" {
+		t.Errorf("ToBytesSchemes不应修改配色模式，实际 %q", combiner.ColorScheme)
+	}
+
+	for name, tc := range map[string]struct {
+		data   []byte
+		bg, fg color.RGBA
+	}{
+		"light": {light, color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}},
+		"dark":  {dark, color.RGBA{20, 20, 30, 255}, color.RGBA{255, 255, 255, 255}},
+	} {
+		img, err := png.Decode(bytes.NewReader(tc.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := color.RGBAModel.Convert(img.At(50, 90)); c != tc.bg {
+			t.Errorf("%s: 背景应为 %v，实际 %v", name, tc.bg, c)
+		}
+		if c := color.RGBAModel.Convert(img.At(90, 90)); c != (color.RGBA{255, 0, 0, 255}) {
+			t.Errorf("%s: 深色未定义的颜色应沿用浅色调色板，实际 %v", name, c)
+		}
+		if _, _, ok := inkColor(img, tc.fg); !ok {
+			t.Errorf("%s: 文本应使用前景色 %v", name, tc.fg)
+		}
+	}
+}
+
+// inkColor 查找指定颜色的像素
+func inkColor(img image.Image, want color.RGBA) (x, y int, ok bool) {
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == want {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,63 @@
+package imgcombine
+
+import (
+	"image"
+	"image/draw"
+)
+
+// RepeatMode 图片平铺方式枚举
+type RepeatMode string
+
+const (
+	NoRepeat   RepeatMode = ""         // 不平铺
+	RepeatX    RepeatMode = "repeat-x" // 只在水平方向平铺一行
+	RepeatY    RepeatMode = "repeat-y" // 只在竖直方向平铺一列
+	RepeatBoth RepeatMode = "repeat"   // 双向平铺填满区域
+)
+
+// tileSize 返回单个平铺单元的尺寸：均未设置时为原图尺寸，只设置一边时按原图比例计算另一边
+func (ie *ImageElement) tileSize(origWidth, origHeight int) (int, int) {
+	w, h := ie.TileWidth, ie.TileHeight
+	switch {
+	case w > 0 && h > 0:
+		return w, h
+	case w > 0 && origWidth > 0:
+		return w, w * origHeight / origWidth
+	case h > 0 && origHeight > 0:
+		return h * origWidth / origHeight, h
+	default:
+		return origWidth, origHeight
+	}
+}
+
+// tile 将图片平铺到width x height的区域，超出区域的部分被裁掉
+// 设置TileStagger时奇数行错开半个单元，配合间距可形成斜向排列的水印图案
+func (ie *ImageElement) tile(img image.Image, width, height int) *image.RGBA {
+	region := image.NewRGBA(image.Rect(0, 0, width, height))
+	bounds := img.Bounds()
+	tw, th := ie.tileSize(bounds.Dx(), bounds.Dy())
+	if tw <= 0 || th <= 0 || width <= 0 || height <= 0 {
+		return region
+	}
+	cell := resizeWith(img, tw, th, ie.ResizeFilter)
+	stepX, stepY := tw+ie.TileSpacing, th+ie.TileSpacing
+
+	rows, cols := 1, 1
+	if ie.Repeat == RepeatY || ie.Repeat == RepeatBoth {
+		rows = (height + stepY - 1) / stepY
+	}
+	if ie.Repeat == RepeatX || ie.Repeat == RepeatBoth {
+		cols = (width+stepX-1)/stepX + 1
+	}
+	for row := 0; row < rows; row++ {
+		offset := 0
+		if ie.TileStagger && row%2 == 1 {
+			offset = -stepX / 2
+		}
+		for col := 0; col < cols; col++ {
+			at := image.Pt(offset+col*stepX, row*stepY)
+			draw.Draw(region, image.Rectangle{Min: at, Max: at.Add(image.Pt(tw, th))}, cell, cell.Bounds().Min, draw.Over)
+		}
+	}
+	return region
+}
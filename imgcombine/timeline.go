@@ -0,0 +1,141 @@
+package imgcombine
+
+import (
+	"image/color"
+
+	"github.com/fogleman/gg"
+)
+
+// StepState 时间轴步骤状态枚举
+type StepState string
+
+const (
+	StepCompleted StepState = "completed" // 已完成
+	StepCurrent   StepState = "current"   // 进行中
+	StepPending   StepState = "pending"   // 未开始
+)
+
+// TimelineStep 时间轴单个步骤
+type TimelineStep struct {
+	Label string    // 步骤标签
+	State StepState // 步骤状态
+}
+
+// TimelineElement 步骤时间轴元素，用于订单状态、引导流程等分享图
+// 水平方向时X,Y为第一个节点的圆心，标签绘制在节点下方；
+// 垂直方向时标签绘制在节点右侧
+type TimelineElement struct {
+	X, Y           int            // 第一个节点圆心坐标
+	Length         int            // 首尾节点圆心之间的距离
+	Vertical       bool           // 是否垂直排列
+	Steps          []TimelineStep // 步骤列表
+	NodeRadius     float64        // 节点半径
+	LineWidth      float64        // 连接线宽度
+	CompletedColor color.Color    // 已完成颜色
+	CurrentColor   color.Color    // 进行中颜色
+	PendingColor   color.Color    // 未开始颜色
+	FontSize       float64        // 标签字体大小
+	LabelColor     color.Color    // 标签颜色
+	LabelGap       float64        // 标签与节点之间的间距
+	FontPaths      []string       // 自定义字体路径列表
+}
+
+// AddTimelineElement 添加步骤时间轴元素
+func (ic *ImageCombiner) AddTimelineElement(steps []TimelineStep, x, y, length int) *TimelineElement {
+	element := &TimelineElement{
+		X:              x,
+		Y:              y,
+		Length:         length,
+		Steps:          steps,
+		NodeRadius:     10,
+		LineWidth:      4,
+		CompletedColor: color.RGBA{82, 196, 26, 255},
+		CurrentColor:   color.RGBA{24, 144, 255, 255},
+		PendingColor:   color.RGBA{200, 200, 200, 255},
+		FontSize:       20,
+		LabelColor:     color.RGBA{80, 80, 80, 255},
+		LabelGap:       12,
+		FontPaths:      ic.FontPaths,
+	}
+
+	ic.AddElement(element)
+	return element
+}
+
+// stateColor 返回步骤状态对应的颜色
+func (te *TimelineElement) stateColor(state StepState) color.Color {
+	switch state {
+	case StepCompleted:
+		return te.CompletedColor
+	case StepCurrent:
+		return te.CurrentColor
+	default:
+		return te.PendingColor
+	}
+}
+
+// nodeCenter 计算第i个节点的圆心
+func (te *TimelineElement) nodeCenter(i int) (float64, float64) {
+	offset := 0.0
+	if len(te.Steps) > 1 {
+		offset = float64(te.Length) * float64(i) / float64(len(te.Steps)-1)
+	}
+	if te.Vertical {
+		return float64(te.X), float64(te.Y) + offset
+	}
+	return float64(te.X) + offset, float64(te.Y)
+}
+
+// Draw 实现CombineElement接口
+func (te *TimelineElement) Draw(g *gg.Context, canvasWidth int) {
+	if len(te.Steps) == 0 {
+		return
+	}
+
+	g.Push()
+	defer g.Pop()
+
+	// 连接线：下一步已开始则视为已走过
+	g.SetLineWidth(te.LineWidth)
+	for i := 0; i < len(te.Steps)-1; i++ {
+		x1, y1 := te.nodeCenter(i)
+		x2, y2 := te.nodeCenter(i + 1)
+		if te.Steps[i+1].State == StepPending {
+			g.SetColor(te.PendingColor)
+		} else {
+			g.SetColor(te.CompletedColor)
+		}
+		g.DrawLine(x1, y1, x2, y2)
+		g.Stroke()
+	}
+
+	// 节点：进行中的节点额外绘制外环
+	for i, step := range te.Steps {
+		cx, cy := te.nodeCenter(i)
+		c := te.stateColor(step.State)
+		if step.State == StepCurrent {
+			g.SetColor(c)
+			g.SetLineWidth(te.LineWidth / 2)
+			g.DrawCircle(cx, cy, te.NodeRadius+te.LineWidth)
+			g.Stroke()
+		}
+		g.SetColor(c)
+		g.DrawCircle(cx, cy, te.NodeRadius)
+		g.Fill()
+	}
+
+	// 标签
+	loadFontFace(g, te.FontPaths, te.FontSize)
+	g.SetColor(te.LabelColor)
+	for i, step := range te.Steps {
+		if step.Label == "" {
+			continue
+		}
+		cx, cy := te.nodeCenter(i)
+		if te.Vertical {
+			g.DrawStringAnchored(step.Label, cx+te.NodeRadius+te.LabelGap, cy, 0, 0.35)
+		} else {
+			g.DrawStringAnchored(step.Label, cx, cy+te.NodeRadius+te.LabelGap, 0.5, 1)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package imgcombine
+
+import (
+	"testing"
+)
+
+// TestTimelineElement 测试时间轴节点状态颜色
+func TestTimelineElement(t *testing.T) {
+	combiner := NewImageCombiner(400, 100)
+
+	timeline := combiner.AddTimelineElement([]TimelineStep{
+		{Label: "下单", State: StepCompleted},
+		{Label: "发货", State: StepCurrent},
+		{Label: "签收", State: StepPending},
+	}, 50, 30, 300)
+
+	img, err := combiner.Combine()
+	if err != nil {
+		t.Fatalf("合成失败: %v", err)
+	}
+
+	expect := []struct {
+		x, y int
+		name string
+		want [3]uint32
+	}{
+		{50, 30, "已完成节点", rgb(timeline.CompletedColor)},
+		{200, 30, "进行中节点", rgb(timeline.CurrentColor)},
+		{350, 30, "未开始节点", rgb(timeline.PendingColor)},
+		{125, 30, "已走过连接线", rgb(timeline.CompletedColor)},
+		{275, 30, "未走过连接线", rgb(timeline.PendingColor)},
+	}
+	for _, e := range expect {
+		if got := rgb(img.At(e.x, e.y)); got != e.want {
+			t.Errorf("%s颜色错误: got %v want %v", e.name, got, e.want)
+		}
+	}
+}
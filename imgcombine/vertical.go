@@ -0,0 +1,100 @@
+package imgcombine
+
+import (
+	"math"
+	"strings"
+
+	"github.com/fogleman/gg"
+)
+
+// verticalRotated 竖排时需要旋转90度绘制的字符（括号、破折号等）
+const verticalRotated = "（）【】「」『』《》〈〉〔〕［］｛｝()[]{}<>—～…‥-~"
+
+// verticalColumns 将文本拆分为竖排的列，换列规则与横排换行一致
+// 每个字符占一个字号的高度，设置MaxLineHeight时按列高换列，并应用最大列数限制
+func (te *TextElement) verticalColumns() [][]rune {
+	runes := []rune(normalizeNewlines(te.Text))
+	perColumn := len(runes)
+	if te.MaxLineHeight > 0 && te.FontSize > 0 {
+		perColumn = max(int(float64(te.MaxLineHeight)/te.FontSize), 1)
+	}
+	if len(runes) == 0 {
+		return [][]rune{runes}
+	}
+
+	var columns [][]rune
+	current := []rune{}
+	for _, r := range runes {
+		// 换行符强制换列
+		if r == '\n' {
+			columns = append(columns, current)
+			current = []rune{}
+			continue
+		}
+		if len(current) < perColumn {
+			current = append(current, r)
+			continue
+		}
+
+		next := []rune{r}
+		// 避头尾规则：列首不能是闭合标点，列尾不能是开启标点
+		for len(current) > 1 && (isLineStartProhibited(next[0]) || isLineEndProhibited(current[len(current)-1])) {
+			next = append([]rune{current[len(current)-1]}, next...)
+			current = current[:len(current)-1]
+		}
+		columns = append(columns, current)
+		current = next
+	}
+	columns = append(columns, current)
+
+	if te.MaxColumnCount > 0 && len(columns) > te.MaxColumnCount {
+		columns = columns[:te.MaxColumnCount]
+		if te.Ellipsis != "" {
+			last := columns[len(columns)-1]
+			ellipsis := []rune(te.Ellipsis)
+			keep := max(min(len(last), perColumn-len(ellipsis)), 0)
+			columns[len(columns)-1] = append(append([]rune{}, last[:keep]...), ellipsis...)
+		}
+	}
+	return columns
+}
+
+// columnSpacing 返回竖排相邻两列的间距，默认1.5倍字体大小
+func (te *TextElement) columnSpacing() float64 {
+	if te.LineHeight > 0 {
+		return te.LineHeight
+	}
+	return te.FontSize * 1.5
+}
+
+// verticalWidth 计算竖排文本占用的宽度
+func (te *TextElement) verticalWidth() float64 {
+	columns := te.verticalColumns()
+	return float64(len(columns)-1)*te.columnSpacing() + te.FontSize
+}
+
+// drawVertical 绘制竖排文本
+// (X, Y)为第一列（最右列）的左上角，后续列依次向左排列
+func (te *TextElement) drawVertical(g *gg.Context, style synthStyle) {
+	for i, column := range te.verticalColumns() {
+		centerX := float64(te.X) + te.FontSize/2 - float64(i)*te.columnSpacing()
+		for j, r := range column {
+			centerY := float64(te.Y) + (float64(j)+0.5)*te.FontSize
+			if strings.ContainsRune(verticalRotated, r) {
+				g.Push()
+				g.RotateAbout(math.Pi/2, centerX, centerY)
+				style.drawString(g, string(r), centerX, centerY, 0.5, 0.35)
+				g.Pop()
+			} else {
+				style.drawString(g, string(r), centerX, centerY, 0.5, 0.35)
+			}
+		}
+
+		// 删除线沿列中心纵向绘制
+		if te.StrikeThrough && len(column) > 0 {
+			g.SetLineWidth(1.0)
+			g.DrawLine(centerX, float64(te.Y), centerX, float64(te.Y)+float64(len(column))*te.FontSize)
+			g.Stroke()
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package imgcombine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"os/exec"
+	"time"
+)
+
+// FFmpegPath ffmpeg可执行文件路径，用于从视频中截取帧
+var FFmpegPath = "ffmpeg"
+
+// ExternalToolTimeout 调用ffmpeg等外部工具的超时时间，超时后终止进程并返回错误
+var ExternalToolTimeout = time.Minute
+
+// LoadVideoFrame 从视频（本地路径或URL）中截取指定时间点的一帧
+// 依赖外部ffmpeg，由ffmpeg负责解码并以PNG格式通过管道输出
+func LoadVideoFrame(videoPath string, at time.Duration) (image.Image, error) {
+	args := []string{
+		"-ss", fmt.Sprintf("%.3f", at.Seconds()),
+		"-i", videoPath,
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "png",
+		"-loglevel", "error",
+		"-",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ExternalToolTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, FFmpegPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("extract video frame: timed out after %s", ExternalToolTimeout)
+		}
+		return nil, fmt.Errorf("extract video frame: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("extract video frame: no frame at %s", at)
+	}
+
+	return decodeImage(&stdout)
+}
+
+// AddVideoFrameElement 添加视频帧图片元素，截取视频指定时间点的画面作为图片
+func (ic *ImageCombiner) AddVideoFrameElement(videoPath string, at time.Duration, x, y int, zoomMode ZoomMode) (*ImageElement, error) {
+	img, err := LoadVideoFrame(videoPath, at)
+	if err != nil {
+		return nil, err
+	}
+
+	element := &ImageElement{
+		ImagePath:  videoPath,
+		VideoFrame: true,
+		FrameAt:    at,
+		image:      img,
+		X:          x,
+		Y:          y,
+		ZoomMode:   zoomMode,
+		Alpha:      255,
+	}
+
+	ic.AddElement(element)
+	return element, nil
+}
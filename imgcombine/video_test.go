@@ -0,0 +1,77 @@
+package imgcombine
+
+import (
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVideoFrame 以模拟的ffmpeg测试截帧参数、JSON恢复与超时
+func TestVideoFrame(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("模拟的ffmpeg为shell脚本")
+	}
+	dir := t.TempDir()
+	framePath := filepath.Join(dir, "frame.png")
+	f, err := os.Create(framePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, solidImage(16, 9, color.RGBA{255, 0, 0, 255}))
+	f.Close()
+
+	// 记录参数并输出预先生成的帧
+	argsPath := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsPath + "\ncat " + framePath + "\n"
+	tool := filepath.Join(dir, "ffmpeg")
+	if err := os.WriteFile(tool, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { FFmpegPath = path }(FFmpegPath)
+	FFmpegPath = tool
+
+	combiner := NewImageCombiner(20, 20)
+	element, err := combiner.AddVideoFrameElement("clip.mp4", 1500*time.Millisecond, 0, 0, Origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := element.image.Bounds(); b.Dx() != 16 || b.Dy() != 9 {
+		t.Errorf("帧尺寸错误: %v", b)
+	}
+	args, _ := os.ReadFile(argsPath)
+	if want := "-ss 1.500 -i clip.mp4 -frames:v 1 -f image2pipe -vcodec png -loglevel error -\n"; string(args) != want {
+		t.Errorf("ffmpeg参数错误: %q", args)
+	}
+
+	data, err := json.Marshal(combiner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(argsPath)
+	if err := json.Unmarshal(data, &ImageCombiner{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(argsPath); err != nil {
+		t.Error("JSON恢复时应重新截帧")
+	}
+
+	// 卡住的ffmpeg在超时后被终止
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\nexec sleep 5\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(timeout time.Duration) { ExternalToolTimeout = timeout }(ExternalToolTimeout)
+	ExternalToolTimeout = 100 * time.Millisecond
+	start := time.Now()
+	if _, err := LoadVideoFrame("clip.mp4", 0); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("应返回超时错误，实际 %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("超时后应立即终止ffmpeg")
+	}
+}
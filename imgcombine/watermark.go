@@ -0,0 +1,98 @@
+package imgcombine
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// ErrLossyWatermark 设置了隐形水印但输出为有损格式、灰度或黑白，水印会被破坏
+var ErrLossyWatermark = errors.New("invisible watermark requires a lossless output format")
+
+// watermarkMagic 隐形水印数据头，用于识别图片中是否存在水印
+var watermarkMagic = []byte("ICWM")
+
+// EmbedWatermark 将payload以最低有效位(LSB)方式写入图片的RGB通道
+// 数据格式为：魔数(4字节) + 长度(4字节，大端) + payload
+// LSB水印只能在PNG等无损格式中保留，JPG压缩会破坏水印
+func EmbedWatermark(img image.Image, payload string) (*image.NRGBA, error) {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Src)
+
+	data := make([]byte, 0, len(watermarkMagic)+4+len(payload))
+	data = append(data, watermarkMagic...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(payload)))
+	data = append(data, payload...)
+
+	// 每个像素的R、G、B各承载1位
+	capacity := bounds.Dx() * bounds.Dy() * 3 / 8
+	if len(data) > capacity {
+		return nil, fmt.Errorf("watermark payload too large: %d bytes, capacity %d", len(payload), capacity-len(watermarkMagic)-4)
+	}
+
+	bit := 0
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			idx := lsbIndex(out, bit)
+			out.Pix[idx] = out.Pix[idx]&^1 | (b>>uint(i))&1
+			bit++
+		}
+	}
+	return out, nil
+}
+
+// checkWatermarkOutput 设置了隐形水印时检查输出能否保留水印，lossless为输出编码是否无损
+// 灰度、黑白颜色模式同样会丢失水印所在的最低位
+func (ic *ImageCombiner) checkWatermarkOutput(lossless bool) error {
+	if ic.InvisibleWatermark != "" && (!lossless || ic.ColorMode != ColorModeRGB) {
+		return ErrLossyWatermark
+	}
+	return nil
+}
+
+// DetectWatermark 从图片中读取EmbedWatermark写入的水印，未检测到时返回false
+func DetectWatermark(img image.Image) (string, bool) {
+	bounds := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok {
+		nrgba = image.NewNRGBA(bounds)
+		draw.Draw(nrgba, bounds, img, bounds.Min, draw.Src)
+	}
+
+	totalBits := bounds.Dx() * bounds.Dy() * 3
+	bit := 0
+	readBytes := func(n int) ([]byte, bool) {
+		if bit+n*8 > totalBits {
+			return nil, false
+		}
+		buf := make([]byte, n)
+		for i := range buf {
+			for j := 0; j < 8; j++ {
+				buf[i] = buf[i]<<1 | nrgba.Pix[lsbIndex(nrgba, bit)]&1
+				bit++
+			}
+		}
+		return buf, true
+	}
+
+	header, ok := readBytes(len(watermarkMagic) + 4)
+	if !ok || string(header[:len(watermarkMagic)]) != string(watermarkMagic) {
+		return "", false
+	}
+	payload, ok := readBytes(int(binary.BigEndian.Uint32(header[len(watermarkMagic):])))
+	if !ok {
+		return "", false
+	}
+	return string(payload), true
+}
+
+// lsbIndex 返回第bit位数据在像素数组中对应的字节下标（跳过Alpha通道）
+func lsbIndex(img *image.NRGBA, bit int) int {
+	pixel, channel := bit/3, bit%3
+	width := img.Rect.Dx()
+	x, y := pixel%width, pixel/width
+	return y*img.Stride + x*4 + channel
+}
@@ -0,0 +1,102 @@
+package imgcombine
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// TestInvisibleWatermark 测试隐形水印嵌入PNG后可被检测
+func TestInvisibleWatermark(t *testing.T) {
+	combiner := NewImageCombiner(100, 100)
+	combiner.OutputFormat = PNG
+	combiner.InvisibleWatermark = "campaign-42/user-1001"
+	rect := combiner.AddRectangleElement(10, 10, 50, 50)
+	rect.Color = color.RGBA{255, 0, 0, 255}
+
+	data, err := combiner.ToBytes()
+	if err != nil {
+		t.Fatalf("生成图片失败: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("解码图片失败: %v", err)
+	}
+
+	payload, ok := DetectWatermark(img)
+	if !ok || payload != "campaign-42/user-1001" {
+		t.Errorf("水印检测失败: %q %v", payload, ok)
+	}
+
+	// 灰度、黑白及ESC/POS输出会丢失水印，应返回错误
+	for _, mode := range []ColorMode{ColorModeGray, ColorModeMono} {
+		combiner.ColorMode = mode
+		if _, err := combiner.ToBytes(); !errors.Is(err, ErrLossyWatermark) {
+			t.Errorf("%s: 应返回ErrLossyWatermark，实际 %v", mode, err)
+		}
+		if err := combiner.WriteGIF(&bytes.Buffer{}, 1, 1, false, nil); !errors.Is(err, ErrLossyWatermark) {
+			t.Errorf("%s: GIF输出应返回ErrLossyWatermark，实际 %v", mode, err)
+		}
+	}
+	combiner.ColorMode = ColorModeRGB
+	if _, err := combiner.ToESCPOS(); !errors.Is(err, ErrLossyWatermark) {
+		t.Errorf("ESC/POS输出应返回ErrLossyWatermark，实际 %v", err)
+	}
+
+	// JPG压缩会破坏水印，应返回错误而不是静默输出
+	combiner.OutputFormat = JPG
+	if _, err := combiner.ToBytes(); !errors.Is(err, ErrLossyWatermark) {
+		t.Errorf("JPG输出应返回ErrLossyWatermark，实际 %v", err)
+	}
+	if err := combiner.Save(t.TempDir() + "/out.jpg"); !errors.Is(err, ErrLossyWatermark) {
+		t.Errorf("保存JPG应返回ErrLossyWatermark，实际 %v", err)
+	}
+
+	// 未嵌入水印的图片
+	plain, _ := NewImageCombiner(100, 100).Combine()
+	if _, ok := DetectWatermark(plain); ok {
+		t.Error("未嵌入水印的图片不应检测到水印")
+	}
+
+	// 超出容量
+	if _, err := EmbedWatermark(plain, strings.Repeat("x", 4000)); err == nil {
+		t.Error("超出容量应返回错误")
+	}
+}
+
+// TestInvisibleWatermarkGIF 测试GIF动画保留水印，颜色过多需要抖动时返回错误
+func TestInvisibleWatermarkGIF(t *testing.T) {
+	combiner := NewImageCombiner(60, 60)
+	combiner.InvisibleWatermark = "gif-1"
+	rect := combiner.AddRectangleElement(10, 10, 20, 20)
+	rect.Color = color.RGBA{0, 0, 255, 255}
+
+	var buf bytes.Buffer
+	if err := combiner.WriteGIF(&buf, 2, 10, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload, ok := DetectWatermark(anim.Image[0]); !ok || payload != "gif-1" {
+		t.Errorf("GIF帧水印检测失败: %q %v", payload, ok)
+	}
+
+	// 渐变超过256种颜色，抖动会破坏水印
+	gradient := image.NewRGBA(image.Rect(0, 0, 60, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 60; x++ {
+			gradient.Set(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 128, 255})
+		}
+	}
+	combiner.AddImageElementFromImage(gradient, 0, 0, WidthHeight)
+	if err := combiner.WriteGIF(&bytes.Buffer{}, 2, 10, false, nil); !errors.Is(err, ErrLossyWatermark) {
+		t.Errorf("需要抖动时应返回ErrLossyWatermark，实际 %v", err)
+	}
+}